@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/peter/kcplens/internal/kcp"
+)
+
+const applyUsage = "apply [-f] <file|dir> [flags]"
+
+// runApply implements "kcplens apply": it prints the server-side dry-run
+// diff of the manifests and applies them after confirmation.
+func runApply(args []string) error {
+	fs := flag.NewFlagSet("apply", flag.ExitOnError)
+	opts := addClientFlags(fs)
+	file := fs.String("f", "", "manifest file or directory to apply")
+	workspace := fs.String("w", "", "workspace to apply to (default: the kubeconfig's workspace)")
+	dryRun := fs.Bool("dry-run", false, "only show the diff, do not apply")
+	yes := fs.Bool("y", false, "apply without asking for confirmation")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kcplens %s\n", applyUsage)
+		fs.PrintDefaults()
+	}
+
+	// The file may also be given as the only positional argument.
+	positional := parseArgs(fs, args)
+	if len(positional) > 1 || (len(positional) == 1 && *file != "") {
+		return fmt.Errorf("usage: kcplens %s", applyUsage)
+	}
+	if len(positional) == 1 {
+		*file = positional[0]
+	}
+	if *file == "" {
+		return fmt.Errorf("-f is required")
+	}
+
+	cfg, cm, err := opts.clientManager()
+	if err != nil {
+		return err
+	}
+	ws := *workspace
+	if ws == "" {
+		ws = cm.CurrentWorkspace()
+	}
+
+	manifests, err := kcp.ReadManifests(*file)
+	if err != nil {
+		return err
+	}
+	if len(manifests) == 0 {
+		return fmt.Errorf("no objects found in %s", *file)
+	}
+
+	ctx := context.Background()
+	plans, err := cm.PlanApply(ctx, ws, manifests)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, plan := range plans {
+		switch plan.Action() {
+		case "error":
+			failed++
+			fmt.Printf("%s %s: %v\n", plan.Manifest.Object.GetKind(), plan.Manifest.Object.GetName(), plan.Err)
+		case "deferred":
+			fmt.Printf("%s %s: kind not served yet, planned again once the objects before it are applied\n", plan.Manifest.Object.GetKind(), plan.Manifest.Object.GetName())
+		case "unchanged":
+			fmt.Printf("%s: unchanged\n", plan.Ref)
+		default:
+			fmt.Printf("%s: %s\n%s\n", plan.Ref, plan.Action(), plan.Diff)
+		}
+	}
+
+	if *dryRun {
+		return nil
+	}
+	if cm.ReadOnly() {
+		return kcp.ErrReadOnly
+	}
+
+	in := bufio.NewReader(os.Stdin)
+	if cfg.IsProtected(ws) {
+		fmt.Printf("Workspace %s is protected. Type the workspace path to confirm: ", ws)
+		if line, _ := in.ReadString('\n'); strings.TrimSpace(line) != ws {
+			return fmt.Errorf("apply cancelled")
+		}
+	} else if !*yes {
+		fmt.Printf("Apply %d objects (%d failing) to %s? [y/N] ", len(plans), failed, ws)
+		if line, _ := in.ReadString('\n'); strings.ToLower(strings.TrimSpace(line)) != "y" {
+			return fmt.Errorf("apply cancelled")
+		}
+	}
+
+	applied := 0
+	for i, err := range cm.ApplyManifests(ctx, plans) {
+		if err != nil {
+			fmt.Printf("failed: %s: %v\n", plans[i].Manifest.Object.GetName(), err)
+			continue
+		}
+		fmt.Printf("applied: %s\n", plans[i].Ref)
+		applied++
+	}
+	if applied < len(plans) {
+		return fmt.Errorf("applied %d of %d objects", applied, len(plans))
+	}
+	return nil
+}
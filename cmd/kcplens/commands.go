@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/peter/kcplens/internal/config"
+	"github.com/peter/kcplens/internal/kcp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// subcommands maps the first argument to its implementation. Without a
+// subcommand kcplens starts the TUI.
+var subcommands = map[string]func(args []string) error{
+	"apply":     runApply,
+	"tree":      runTree,
+	"apis":      runAPIs,
+	"resources": runResources,
+	"get":       runGet,
+	"search":    runSearch,
+}
+
+// readCommand bundles the flags and setup shared by the read-only subcommands.
+type readCommand struct {
+	fs      *flag.FlagSet
+	client  *clientOptions
+	output  *string
+	usage   string
+	minArgs int
+	maxArgs int
+}
+
+func newReadCommand(name, usage string, minArgs, maxArgs int) *readCommand {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	cmd := &readCommand{
+		fs:      fs,
+		client:  addClientFlags(fs),
+		output:  addOutputFlag(fs),
+		usage:   usage,
+		minArgs: minArgs,
+		maxArgs: maxArgs,
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: kcplens %s\n", usage)
+		fs.PrintDefaults()
+	}
+	return cmd
+}
+
+// parse parses args and creates the client manager.
+func (r *readCommand) parse(args []string) ([]string, outputFormat, *config.Config, *kcp.ClientManager, error) {
+	positional := parseArgs(r.fs, args)
+	if len(positional) < r.minArgs || len(positional) > r.maxArgs {
+		return nil, "", nil, nil, fmt.Errorf("usage: kcplens %s", r.usage)
+	}
+
+	format, err := parseOutputFormat(*r.output)
+	if err != nil {
+		return nil, "", nil, nil, err
+	}
+
+	cfg, cm, err := r.client.clientManager()
+	if err != nil {
+		return nil, "", nil, nil, err
+	}
+	return positional, format, cfg, cm, nil
+}
+
+// workspaceOutput is the JSON and YAML form of a workspace tree.
+type workspaceOutput struct {
+	Name     string            `json:"name"`
+	Path     string            `json:"path"`
+	Phase    string            `json:"phase,omitempty"`
+	NoAccess bool              `json:"noAccess,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Children []workspaceOutput `json:"children,omitempty"`
+}
+
+func newWorkspaceOutput(node *kcp.WorkspaceNode) workspaceOutput {
+	out := workspaceOutput{Name: node.Name, Path: node.Path, Phase: node.Phase, NoAccess: node.Inaccessible()}
+	if node.ListErr != nil && !out.NoAccess {
+		out.Error = node.ListErr.Error()
+	}
+	for _, child := range node.Children {
+		out.Children = append(out.Children, newWorkspaceOutput(child))
+	}
+	return out
+}
+
+func runTree(args []string) error {
+	cmd := newReadCommand("tree", "tree [path] [flags]", 0, 1)
+	positional, format, _, cm, err := cmd.parse(args)
+	if err != nil {
+		return err
+	}
+
+	path := cm.InitialWorkspace()
+	if len(positional) == 1 {
+		path = positional[0]
+	}
+
+	tree, err := cm.DiscoverWorkspaceTree(context.Background(), path)
+	if err != nil {
+		return err
+	}
+
+	if format != outputTable {
+		return printData(os.Stdout, format, newWorkspaceOutput(tree))
+	}
+
+	fmt.Println(tree.Path)
+	printTreeChildren(tree.Children, "")
+	return nil
+}
+
+func printTreeChildren(nodes []*kcp.WorkspaceNode, prefix string) {
+	for i, node := range nodes {
+		branch, indent := "├── ", "│   "
+		if i == len(nodes)-1 {
+			branch, indent = "└── ", "    "
+		}
+		line := prefix + branch + node.Name
+		if node.Phase != "" {
+			line += " (" + node.Phase + ")"
+		}
+		if node.Deleting {
+			line += " [Terminating]"
+		}
+		switch {
+		case node.Inaccessible():
+			line += " [no access]"
+		case node.ListErr != nil:
+			line += fmt.Sprintf(" [error: %v]", node.ListErr)
+		}
+		fmt.Println(line)
+		printTreeChildren(node.Children, prefix+indent)
+	}
+}
+
+// apiOutput is the JSON and YAML form of an API relationship.
+type apiOutput struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	Status        string `json:"status"`
+	ExportName    string `json:"exportName,omitempty"`
+	ExportPath    string `json:"exportPath,omitempty"`
+	ResourceName  string `json:"resourceName,omitempty"`
+	ResourceGroup string `json:"resourceGroup,omitempty"`
+}
+
+func runAPIs(args []string) error {
+	cmd := newReadCommand("apis", "apis <path> [flags]", 1, 1)
+	positional, format, _, cm, err := cmd.parse(args)
+	if err != nil {
+		return err
+	}
+
+	rels, err := cm.DiscoverAPIRelationships(context.Background(), positional[0])
+	if err != nil {
+		return err
+	}
+
+	out := make([]apiOutput, len(rels))
+	rows := make([][]string, len(rels))
+	for i, rel := range rels {
+		out[i] = apiOutput{
+			Name:          rel.Name,
+			Type:          rel.Type,
+			Status:        rel.Status,
+			ExportName:    rel.ExportName,
+			ExportPath:    rel.ExportPath,
+			ResourceName:  rel.ResourceName,
+			ResourceGroup: rel.ResourceGroup,
+		}
+
+		var ref string
+		switch rel.Type {
+		case "Export":
+			ref = rel.ResourceName
+			if rel.ResourceGroup != "" {
+				ref += "." + rel.ResourceGroup
+			}
+		case "Binding":
+			ref = rel.ExportPath + ":" + rel.ExportName
+		}
+		rows[i] = []string{rel.Name, rel.Type, rel.Status, valueOr(ref, "-")}
+	}
+
+	if format != outputTable {
+		return printData(os.Stdout, format, out)
+	}
+	return printTable(os.Stdout, []string{"name", "type", "status", "reference"}, rows)
+}
+
+// resourceOutput is the JSON and YAML form of an available resource type.
+type resourceOutput struct {
+	Resource   string `json:"resource"`
+	Group      string `json:"group"`
+	Version    string `json:"version"`
+	Kind       string `json:"kind"`
+	Namespaced bool   `json:"namespaced"`
+}
+
+func runResources(args []string) error {
+	cmd := newReadCommand("resources", "resources <path> [flags]", 1, 1)
+	positional, format, _, cm, err := cmd.parse(args)
+	if err != nil {
+		return err
+	}
+
+	available, err := cm.DiscoverAvailableResources(context.Background(), positional[0])
+	if err != nil {
+		return err
+	}
+	sort.Slice(available, func(i, j int) bool {
+		return config.ResourceKey(available[i].GVR) < config.ResourceKey(available[j].GVR)
+	})
+
+	out := make([]resourceOutput, len(available))
+	rows := make([][]string, len(available))
+	for i, r := range available {
+		out[i] = resourceOutput{
+			Resource:   r.GVR.Resource,
+			Group:      r.GVR.Group,
+			Version:    r.GVR.Version,
+			Kind:       r.Kind,
+			Namespaced: r.Namespaced,
+		}
+		rows[i] = []string{r.GVR.Resource, valueOr(r.GVR.Group, "-"), r.GVR.Version, r.Kind, strconv.FormatBool(r.Namespaced)}
+	}
+
+	if format != outputTable {
+		return printData(os.Stdout, format, out)
+	}
+	return printTable(os.Stdout, []string{"resource", "group", "version", "kind", "namespaced"}, rows)
+}
+
+func runGet(args []string) error {
+	cmd := newReadCommand("get", "get <resource> -w <path> [flags]", 1, 1)
+	workspace := cmd.fs.String("w", "", "workspace to list the resources in (default: the kubeconfig's workspace)")
+	namespace := cmd.fs.String("n", "", "namespace to list the resources in (default: all)")
+	selector := cmd.fs.String("l", "", "label selector to filter the resources, e.g. app=web")
+	positional, format, cfg, cm, err := cmd.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *workspace == "" {
+		*workspace = cm.InitialWorkspace()
+	}
+
+	ctx := context.Background()
+	gvr, err := cm.ResolveResource(ctx, *workspace, positional[0])
+	if err != nil {
+		return err
+	}
+
+	resources, err := cm.DiscoverResourcesInWorkspace(ctx, *workspace, gvr, *namespace, *selector)
+	if err != nil {
+		return fmt.Errorf("failed to list %s in %s: %w", gvr.Resource, *workspace, err)
+	}
+	return printResources(format, cfg, gvr, resources, false)
+}
+
+func runSearch(args []string) error {
+	cmd := newReadCommand("search", "search <resource> [flags]", 1, 1)
+	workspace := cmd.fs.String("w", "", "workspace used to resolve the resource type (default: the kubeconfig's workspace)")
+	positional, format, cfg, cm, err := cmd.parse(args)
+	if err != nil {
+		return err
+	}
+
+	if *workspace == "" {
+		*workspace = cm.InitialWorkspace()
+	}
+
+	ctx := context.Background()
+	gvr, err := cm.ResolveResource(ctx, *workspace, positional[0])
+	if err != nil {
+		return err
+	}
+
+	resources, err := cm.DiscoverWildcardResources(ctx, gvr)
+	if err != nil {
+		return fmt.Errorf("failed to search %s in all workspaces: %w", gvr.Resource, err)
+	}
+	return printResources(format, cfg, gvr, resources, true)
+}
+
+// printResources prints objects as a List, or as a table with the custom
+// columns configured for their resource type.
+func printResources(format outputFormat, cfg *config.Config, gvr schema.GroupVersionResource, resources []kcp.GenericResource, withWorkspace bool) error {
+	if format != outputTable {
+		items := make([]map[string]interface{}, len(resources))
+		for i, r := range resources {
+			items[i] = r.Raw
+		}
+		return printData(os.Stdout, format, map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "List",
+			"items":      items,
+		})
+	}
+
+	if len(resources) == 0 {
+		fmt.Fprintf(os.Stderr, "No %s found.\n", gvr.Resource)
+		return nil
+	}
+
+	columns := cfg.ColumnsFor(gvr)
+	var header []string
+	if withWorkspace {
+		header = append(header, "workspace")
+	}
+	header = append(header, "namespace", "name")
+	for _, col := range columns {
+		header = append(header, col.Name)
+	}
+
+	rows := make([][]string, len(resources))
+	for i, r := range resources {
+		var row []string
+		if withWorkspace {
+			row = append(row, r.Workspace)
+		}
+		row = append(row, valueOr(r.Namespace, "-"), r.Name)
+		for _, col := range columns {
+			row = append(row, valueOr(strings.TrimSpace(col.Value(r.Raw)), "-"))
+		}
+		rows[i] = row
+	}
+	return printTable(os.Stdout, header, rows)
+}
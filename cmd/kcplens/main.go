@@ -6,15 +6,139 @@ import (
 	"os"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/audit"
+	"github.com/peter/kcplens/internal/config"
 	"github.com/peter/kcplens/internal/kcp"
 	"github.com/peter/kcplens/internal/ui"
+	"github.com/peter/kcplens/internal/ui/keys"
 )
 
+// clientOptions are the flags shared by the TUI and all subcommands.
+type clientOptions struct {
+	kubeconfig  *string
+	contextName *string
+	configPath  *string
+	readOnly    *bool
+}
+
+func addClientFlags(fs *flag.FlagSet) *clientOptions {
+	return &clientOptions{
+		kubeconfig:  fs.String("kubeconfig", "", "path to the kubeconfig file"),
+		contextName: fs.String("context", "", "kubeconfig context to use"),
+		configPath:  fs.String("config", "", "path to the kcplens config file"),
+		readOnly:    fs.Bool("readonly", false, "disable all operations that change objects on the server"),
+	}
+}
+
+func (o *clientOptions) loadConfig() (*config.Config, *audit.Log, error) {
+	cfg, err := config.Load(*o.configPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg.ReadOnly = cfg.ReadOnly || *o.readOnly
+
+	auditLog, err := audit.NewLog(cfg.AuditLog)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to set up audit log: %w", err)
+	}
+	return cfg, auditLog, nil
+}
+
+// newClientManager creates a client for context, or for the current context
+// if it is empty, with the settings from cfg applied.
+func newClientManager(kubeconfig, contextName string, cfg *config.Config, auditLog *audit.Log) (*kcp.ClientManager, error) {
+	var cm *kcp.ClientManager
+	var err error
+	if contextName != "" {
+		cm, err = kcp.NewClientManagerWithContext(kubeconfig, contextName)
+	} else {
+		cm, err = kcp.NewClientManager(kubeconfig)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize KCP client: %w", err)
+	}
+	cm.SetReadOnly(cfg.ReadOnly)
+	cm.SetAuditLog(auditLog)
+	return cm, nil
+}
+
+// clientManager loads the config and creates a client from the flags. The
+// subcommands need to know the kind of server right away, so it is probed
+// here; the TUI probes in the background.
+func (o *clientOptions) clientManager() (*config.Config, *kcp.ClientManager, error) {
+	cfg, auditLog, err := o.loadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	cm, err := newClientManager(*o.kubeconfig, *o.contextName, cfg, auditLog)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cm.SetKind(cm.Probe()); err != nil {
+		return nil, nil, err
+	}
+	return cfg, cm, nil
+}
+
 func main() {
-	kubeconfig := flag.String("kubeconfig", "", "path to the kubeconfig file")
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			if err := run(os.Args[2:]); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	opts := addClientFlags(flag.CommandLine)
+	workspace := flag.String("workspace", "", "workspace to open on start, e.g. root:org-one:team-alpha")
+	view := flag.String("view", "", "view to open on start: workspaces, apis, resources, synctargets or a resource type")
+	syncKubeconfig := flag.Bool("sync-kubeconfig", false, "write the current workspace to the kubeconfig on exit")
+	restore := flag.Bool("restore", false, "reopen the workspace and view of the last session of the context")
+	split := flag.Bool("split", false, "show the highlighted item next to the list")
+	mouse := flag.Bool("mouse", false, "click breadcrumbs and tabs; disables selecting text with the mouse")
+	syncMode := flag.String("sync-mode", string(kcp.SyncContext), "how to write the workspace to the kubeconfig: context or server")
 	flag.Parse()
 
-	contexts, currentCtx, err := kcp.GetContexts(*kubeconfig)
+	mode, err := kcp.ParseKubeconfigSyncMode(*syncMode)
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+
+	if *workspace != "" {
+		if err := kcp.ValidateWorkspacePath(*workspace); err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	cfg, auditLog, err := opts.loadConfig()
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+
+	bookmarks, err := config.LoadBookmarks(cfg.BookmarksPath(), cfg.SharedBookmarks)
+	if err != nil {
+		fmt.Printf("Failed to load bookmarks: %v\n", err)
+		os.Exit(1)
+	}
+
+	sessions, err := config.LoadSessions(cfg.SessionPath())
+	if err != nil {
+		fmt.Printf("Failed to load last sessions: %v\n", err)
+		os.Exit(1)
+	}
+
+	km, err := keys.New(cfg.Keys)
+	if err != nil {
+		fmt.Printf("Invalid key bindings: %v\n", err)
+		os.Exit(1)
+	}
+
+	contexts, currentCtx, err := kcp.GetContexts(*opts.kubeconfig)
 	if err != nil {
 		fmt.Printf("Failed to load kubeconfig contexts: %v\n", err)
 		os.Exit(1)
@@ -22,25 +146,48 @@ func main() {
 
 	var appModel *ui.AppModel
 
-	if len(contexts) > 1 {
-		cm, err := kcp.NewClientManagerWithContext(*kubeconfig, currentCtx)
+	if len(contexts) > 1 && *opts.contextName == "" {
+		cm, err := newClientManager(*opts.kubeconfig, currentCtx, cfg, auditLog)
 		if err != nil {
-			fmt.Printf("Failed to initialize KCP client: %v\n", err)
+			fmt.Printf("%v\n", err)
 			os.Exit(1)
 		}
-		appModel = ui.NewAppModelWithContextSelector(cm, *kubeconfig, contexts, currentCtx)
+		appModel = ui.NewAppModelWithContextSelector(cm, cfg, km, *opts.kubeconfig, contexts, currentCtx)
 	} else {
-		cm, err := kcp.NewClientManager(*kubeconfig)
+		cm, err := newClientManager(*opts.kubeconfig, *opts.contextName, cfg, auditLog)
 		if err != nil {
-			fmt.Printf("Failed to initialize KCP client: %v\n", err)
+			fmt.Printf("%v\n", err)
 			os.Exit(1)
 		}
-		appModel = ui.NewAppModel(cm)
+		appModel = ui.NewAppModel(cm, cfg, km)
 	}
 
-	p := tea.NewProgram(appModel, tea.WithAltScreen())
+	appModel.SetStart(*workspace, *view)
+	appModel.SetSyncKubeconfig(*syncKubeconfig)
+	appModel.SetBookmarks(bookmarks)
+	appModel.SetSessions(sessions, *restore || cfg.RestoreSession)
+	appModel.SetSplit(*split || cfg.SplitPane, cfg.SplitRatio)
+
+	programOpts := []tea.ProgramOption{tea.WithAltScreen()}
+	if *mouse || cfg.Mouse {
+		programOpts = append(programOpts, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(appModel, programOpts...)
 	if _, err := p.Run(); err != nil {
 		fmt.Printf("Alas, there's been an error starting the TUI: %v\n", err)
 		os.Exit(1)
 	}
+
+	if err := appModel.SaveSessions(); err != nil {
+		fmt.Printf("Failed to save the session: %v\n", err)
+	}
+
+	if appModel.SyncKubeconfig() {
+		cm := appModel.ClientManager()
+		if err := cm.WriteWorkspaceToKubeconfig(cm.CurrentWorkspace(), mode); err != nil {
+			fmt.Printf("Failed to update kubeconfig: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Current workspace is %q.\n", cm.CurrentWorkspace())
+	}
 }
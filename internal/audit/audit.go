@@ -0,0 +1,140 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry is one mutating request issued by kcplens.
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Context   string    `json:"context"`
+	User      string    `json:"user"`
+	Workspace string    `json:"workspace"`
+	GVR       string    `json:"gvr"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	Verb      string    `json:"verb"`
+	DryRun    bool      `json:"dryRun"`
+	Diff      string    `json:"diff,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Log appends entries to a JSON-lines file.
+type Log struct {
+	path string
+	mu   sync.Mutex
+	// failed is the last append error not yet taken by TakeError.
+	failed error
+}
+
+// DefaultPath returns the location of the audit log when none is configured.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "kcplens", "audit.jsonl"), nil
+}
+
+// NewLog returns a log writing to path, or to DefaultPath if path is empty.
+func NewLog(path string) (*Log, error) {
+	if path == "" {
+		p, err := DefaultPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine audit log path: %w", err)
+		}
+		path = p
+	}
+	return &Log{path: path}, nil
+}
+
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append writes a single entry to the end of the log. A failure is also
+// kept for TakeError, so that it can be reported apart from the request
+// that was logged.
+func (l *Log) Append(e Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	err := l.append(e)
+	if err != nil {
+		l.failed = err
+	}
+	return err
+}
+
+// TakeError returns the last append failure and forgets it.
+func (l *Log) TakeError() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	err := l.failed
+	l.failed = nil
+	return err
+}
+
+func (l *Log) append(e Entry) error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// Entries reads all entries, newest first. A missing file yields no entries.
+func (l *Log) Entries() ([]Entry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, nil
+}
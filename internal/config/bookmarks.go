@@ -0,0 +1,215 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Bookmark is a saved location in kcplens.
+type Bookmark struct {
+	Name string `json:"name"`
+	// Slot is the number key 1-9 that opens the bookmark, 0 for none.
+	Slot int `json:"slot,omitempty"`
+
+	Context   string `json:"context,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+	// View is workspaces, apis, resources or synctargets. Bookmarks of a
+	// resource list set Resource instead.
+	View      string `json:"view,omitempty"`
+	Resource  string `json:"resource,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Selector  string `json:"selector,omitempty"`
+
+	// Shared is set for bookmarks from a shared file, which kcplens never
+	// changes.
+	Shared bool `json:"-"`
+}
+
+type bookmarkFile struct {
+	Bookmarks []Bookmark `json:"bookmarks"`
+}
+
+// Bookmarks are the user's own bookmarks, stored in a file kcplens writes,
+// and read-only bookmarks shared by a team.
+type Bookmarks struct {
+	path   string
+	own    []Bookmark
+	shared []Bookmark
+	// warnings describe shared files that could not be read.
+	warnings []string
+}
+
+// BookmarksPath returns the file the user's bookmarks are stored in, by
+// default bookmarks.yaml next to the config file.
+func (c *Config) BookmarksPath() string {
+	if c.BookmarksFile != "" {
+		return expandHome(c.BookmarksFile)
+	}
+	return filepath.Join(filepath.Dir(c.path), "bookmarks.yaml")
+}
+
+// LoadBookmarks reads the bookmarks file at path and the shared bookmark
+// files. A missing bookmarks file yields no bookmarks, missing shared files
+// are skipped with a warning. Files that cannot be parsed are an error.
+func LoadBookmarks(path string, shared []string) (*Bookmarks, error) {
+	b := &Bookmarks{path: path}
+
+	own, err := readBookmarkFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	b.own = own
+
+	for _, file := range shared {
+		bookmarks, err := readBookmarkFile(expandHome(file))
+		if errors.Is(err, fs.ErrNotExist) {
+			b.warnings = append(b.warnings, fmt.Sprintf("shared bookmarks %s not found", file))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, bm := range bookmarks {
+			bm.Shared = true
+			b.shared = append(b.shared, bm)
+		}
+	}
+	return b, nil
+}
+
+func readBookmarkFile(path string) ([]Bookmark, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bookmarks %s: %w", path, err)
+	}
+
+	var file bookmarkFile
+	if err := yaml.UnmarshalStrict(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse bookmarks %s: %w", path, err)
+	}
+	for _, bm := range file.Bookmarks {
+		if bm.Name == "" {
+			return nil, fmt.Errorf("bookmark without name in %s", path)
+		}
+		if bm.Slot < 0 || bm.Slot > 9 {
+			return nil, fmt.Errorf("bookmark %s in %s: slot must be between 1 and 9", bm.Name, path)
+		}
+	}
+	return file.Bookmarks, nil
+}
+
+// Path returns the file the user's bookmarks are stored in.
+func (b *Bookmarks) Path() string {
+	return b.path
+}
+
+// Warnings returns the problems with shared files that were skipped.
+func (b *Bookmarks) Warnings() []string {
+	return b.warnings
+}
+
+// All returns the user's bookmarks followed by the shared ones, sorted by
+// name. Own bookmarks hide shared ones of the same name.
+func (b *Bookmarks) All() []Bookmark {
+	seen := make(map[string]bool, len(b.own))
+	all := make([]Bookmark, 0, len(b.own)+len(b.shared))
+	for _, bm := range b.own {
+		seen[bm.Name] = true
+		all = append(all, bm)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+
+	n := len(all)
+	for _, bm := range b.shared {
+		if !seen[bm.Name] {
+			seen[bm.Name] = true
+			all = append(all, bm)
+		}
+	}
+	sort.Slice(all[n:], func(i, j int) bool { return all[n+i].Name < all[n+j].Name })
+	return all
+}
+
+// InSlot returns the bookmark opened by number key slot. Own bookmarks take
+// precedence over shared ones.
+func (b *Bookmarks) InSlot(slot int) (Bookmark, bool) {
+	for _, bm := range b.All() {
+		if bm.Slot == slot {
+			return bm, true
+		}
+	}
+	return Bookmark{}, false
+}
+
+// Add stores bm, replacing a bookmark of the same name. A slot already used
+// by another bookmark is taken over.
+func (b *Bookmarks) Add(bm Bookmark) error {
+	if bm.Name == "" {
+		return fmt.Errorf("bookmark without name")
+	}
+	if bm.Slot < 0 || bm.Slot > 9 {
+		return fmt.Errorf("slot must be between 1 and 9")
+	}
+	bm.Shared = false
+
+	own := make([]Bookmark, 0, len(b.own)+1)
+	for _, existing := range b.own {
+		if existing.Name == bm.Name {
+			continue
+		}
+		if bm.Slot != 0 && existing.Slot == bm.Slot {
+			existing.Slot = 0
+		}
+		own = append(own, existing)
+	}
+	return b.save(append(own, bm))
+}
+
+// Remove deletes the user's bookmark called name.
+func (b *Bookmarks) Remove(name string) error {
+	own := make([]Bookmark, 0, len(b.own))
+	for _, bm := range b.own {
+		if bm.Name != name {
+			own = append(own, bm)
+		}
+	}
+	if len(own) == len(b.own) {
+		return fmt.Errorf("bookmark %s not found", name)
+	}
+	return b.save(own)
+}
+
+func (b *Bookmarks) save(own []Bookmark) error {
+	data, err := yaml.Marshal(bookmarkFile{Bookmarks: own})
+	if err != nil {
+		return fmt.Errorf("failed to encode bookmarks: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for bookmarks: %w", err)
+	}
+	if err := os.WriteFile(b.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write bookmarks %s: %w", b.path, err)
+	}
+	b.own = own
+	return nil
+}
+
+// expandHome replaces a leading ~ with the home directory, so that shared
+// files can be given like in a shell.
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
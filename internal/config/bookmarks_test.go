@@ -0,0 +1,99 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBookmarksAdd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bookmarks.yaml")
+	b, err := LoadBookmarks(path, nil)
+	if err != nil {
+		t.Fatalf("LoadBookmarks() error = %v", err)
+	}
+
+	for _, bm := range []Bookmark{
+		{Name: "shop", Slot: 1, Workspace: "root:shop"},
+		{Name: "team", Slot: 2, Workspace: "root:team"},
+		// Replaces the bookmark of the same name and takes over slot 2.
+		{Name: "shop", Slot: 2, Workspace: "root:shop:prod"},
+	} {
+		if err := b.Add(bm); err != nil {
+			t.Fatalf("Add(%s) error = %v", bm.Name, err)
+		}
+	}
+
+	reloaded, err := LoadBookmarks(path, nil)
+	if err != nil {
+		t.Fatalf("LoadBookmarks() error = %v", err)
+	}
+	all := reloaded.All()
+	if len(all) != 2 {
+		t.Fatalf("got %d bookmarks, want 2", len(all))
+	}
+	if all[0].Name != "shop" || all[0].Workspace != "root:shop:prod" || all[0].Slot != 2 {
+		t.Errorf("shop = %+v, want root:shop:prod in slot 2", all[0])
+	}
+	if all[1].Name != "team" || all[1].Slot != 0 {
+		t.Errorf("team = %+v, want no slot", all[1])
+	}
+}
+
+func TestBookmarksAddInvalid(t *testing.T) {
+	b, err := LoadBookmarks(filepath.Join(t.TempDir(), "bookmarks.yaml"), nil)
+	if err != nil {
+		t.Fatalf("LoadBookmarks() error = %v", err)
+	}
+
+	for _, bm := range []Bookmark{{Slot: 1}, {Name: "x", Slot: 10}, {Name: "x", Slot: -1}} {
+		if err := b.Add(bm); err == nil {
+			t.Errorf("Add(%+v) succeeded, want an error", bm)
+		}
+	}
+	if len(b.All()) != 0 {
+		t.Errorf("invalid bookmarks were added: %+v", b.All())
+	}
+}
+
+func TestLoadBookmarksShared(t *testing.T) {
+	dir := t.TempDir()
+	own := filepath.Join(dir, "bookmarks.yaml")
+	shared := filepath.Join(dir, "team.yaml")
+	writeFile(t, own, "bookmarks:\n- name: shop\n  slot: 1\n")
+	writeFile(t, shared, "bookmarks:\n- name: shop\n  slot: 2\n- name: prod\n  slot: 1\n")
+
+	b, err := LoadBookmarks(own, []string{shared, filepath.Join(dir, "missing.yaml")})
+	if err != nil {
+		t.Fatalf("LoadBookmarks() error = %v", err)
+	}
+	if warnings := b.Warnings(); len(warnings) != 1 || !strings.Contains(warnings[0], "missing.yaml") {
+		t.Errorf("Warnings() = %v, want one for missing.yaml", warnings)
+	}
+
+	all := b.All()
+	if len(all) != 2 || all[0].Name != "shop" || all[0].Shared || all[1].Name != "prod" || !all[1].Shared {
+		t.Errorf("All() = %+v, want own shop then shared prod", all)
+	}
+	if bm, ok := b.InSlot(1); !ok || bm.Name != "shop" {
+		t.Errorf("InSlot(1) = %+v, want the own bookmark shop", bm)
+	}
+}
+
+func TestLoadBookmarksInvalidShared(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "team.yaml")
+	writeFile(t, shared, "bookmarks:\n- slot: 1\n")
+
+	if _, err := LoadBookmarks(filepath.Join(dir, "bookmarks.yaml"), []string{shared}); err == nil {
+		t.Error("LoadBookmarks() succeeded with an invalid shared file")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// ColumnSpec describes a user-defined column. Exactly one of JSONPath or
+// Template must be set.
+type ColumnSpec struct {
+	Name     string `json:"name"`
+	JSONPath string `json:"jsonPath,omitempty"`
+	Template string `json:"template,omitempty"`
+}
+
+// Column is a compiled ColumnSpec that can extract a value from an object.
+type Column struct {
+	Name string
+
+	jp   *jsonpath.JSONPath
+	tmpl *template.Template
+}
+
+// CompileColumns parses the JSONPath or template expression of each spec.
+func CompileColumns(specs []ColumnSpec) ([]Column, error) {
+	cols := make([]Column, 0, len(specs))
+	for _, spec := range specs {
+		col, err := compileColumn(spec)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+func compileColumn(spec ColumnSpec) (Column, error) {
+	if spec.Name == "" {
+		return Column{}, fmt.Errorf("column without name")
+	}
+	if (spec.JSONPath == "") == (spec.Template == "") {
+		return Column{}, fmt.Errorf("column %s: exactly one of jsonPath or template must be set", spec.Name)
+	}
+
+	col := Column{Name: spec.Name}
+
+	if spec.JSONPath != "" {
+		expr := spec.JSONPath
+		if !strings.Contains(expr, "{") {
+			expr = "{" + expr + "}"
+		}
+		jp := jsonpath.New(spec.Name).AllowMissingKeys(true)
+		if err := jp.Parse(expr); err != nil {
+			return Column{}, fmt.Errorf("column %s: invalid jsonPath: %w", spec.Name, err)
+		}
+		col.jp = jp
+		return col, nil
+	}
+
+	tmpl, err := template.New(spec.Name).Option("missingkey=zero").Parse(spec.Template)
+	if err != nil {
+		return Column{}, fmt.Errorf("column %s: invalid template: %w", spec.Name, err)
+	}
+	col.tmpl = tmpl
+	return col, nil
+}
+
+// Value evaluates the column against a raw object. Evaluation errors are
+// rendered inline so a single bad object does not break the list.
+func (c Column) Value(obj map[string]interface{}) string {
+	var buf bytes.Buffer
+
+	switch {
+	case c.jp != nil:
+		if err := c.jp.Execute(&buf, obj); err != nil {
+			return "<error>"
+		}
+	case c.tmpl != nil:
+		if err := c.tmpl.Execute(&buf, obj); err != nil {
+			return "<error>"
+		}
+	}
+
+	return strings.ReplaceAll(buf.String(), "<no value>", "")
+}
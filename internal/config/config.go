@@ -0,0 +1,155 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// Config holds the user settings read from the kcplens config file.
+type Config struct {
+	// Columns maps a resource key like "widgets.example.kcp.io" to the
+	// extra columns shown in the resource instance list.
+	Columns map[string][]ColumnSpec `json:"columns,omitempty"`
+
+	// ReadOnly disables every operation that changes objects on the server.
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// ProtectedWorkspaces are workspace path patterns like "root:prod:*".
+	// Changes in matching workspaces must be confirmed by typing the path.
+	ProtectedWorkspaces []string `json:"protectedWorkspaces,omitempty"`
+
+	// AuditLog is the path of the audit log of all changes made by kcplens.
+	AuditLog string `json:"auditLog,omitempty"`
+
+	// RestoreSession reopens the workspace and view of the last session of
+	// each context, like the -restore flag.
+	RestoreSession bool `json:"restoreSession,omitempty"`
+
+	// BookmarksFile is where bookmarks are stored, by default bookmarks.yaml
+	// next to the config file.
+	BookmarksFile string `json:"bookmarksFile,omitempty"`
+
+	// SharedBookmarks are files with bookmarks shared by a team. They are
+	// listed after the user's own bookmarks and never written.
+	SharedBookmarks []string `json:"sharedBookmarks,omitempty"`
+
+	// SplitPane shows the highlighted item next to the list, like the -split
+	// flag.
+	SplitPane bool `json:"splitPane,omitempty"`
+
+	// SplitRatio is the share of the terminal width taken by the list in
+	// the split layout, between 0.25 and 0.75.
+	SplitRatio float64 `json:"splitRatio,omitempty"`
+
+	// Mouse lets breadcrumbs and tabs be clicked, like the -mouse flag. The
+	// terminal then no longer selects text with the mouse.
+	Mouse bool `json:"mouse,omitempty"`
+
+	// Keys overrides key bindings by action name, e.g. "newTab": ["T"].
+	// An empty list disables the action.
+	Keys map[string][]string `json:"keys,omitempty"`
+
+	path    string
+	columns map[string][]Column
+}
+
+// DefaultPath returns the location of the config file when none is given.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "kcplens", "config.yaml"), nil
+}
+
+// Load reads the config file at path, or at DefaultPath if path is empty.
+// A missing file yields an empty config.
+func Load(path string) (*Config, error) {
+	if path == "" {
+		p, err := DefaultPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine config path: %w", err)
+		}
+		path = p
+	}
+
+	cfg := &Config{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	if err == nil {
+		if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+	}
+
+	for _, pattern := range cfg.ProtectedWorkspaces {
+		if _, err := matchWorkspace(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid protected workspace pattern %q: %w", pattern, err)
+		}
+	}
+
+	cfg.columns = make(map[string][]Column, len(cfg.Columns))
+	for key, specs := range cfg.Columns {
+		cols, err := CompileColumns(specs)
+		if err != nil {
+			return nil, fmt.Errorf("invalid columns for %s: %w", key, err)
+		}
+		cfg.columns[key] = cols
+	}
+
+	return cfg, nil
+}
+
+// Path returns the file the config was loaded from.
+func (c *Config) Path() string {
+	if c == nil {
+		return ""
+	}
+	return c.path
+}
+
+// IsProtected reports whether a workspace path matches one of the protected
+// workspace patterns. A "*" matches any characters including ":", so
+// "root:prod:*" covers all workspaces below root:prod.
+func (c *Config) IsProtected(workspace string) bool {
+	if c == nil {
+		return false
+	}
+	for _, pattern := range c.ProtectedWorkspaces {
+		if ok, _ := matchWorkspace(pattern, workspace); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func matchWorkspace(pattern, workspace string) (bool, error) {
+	return path.Match(pattern, workspace)
+}
+
+// ColumnsFor returns the compiled custom columns configured for a GVR.
+func (c *Config) ColumnsFor(gvr schema.GroupVersionResource) []Column {
+	if c == nil {
+		return nil
+	}
+	return c.columns[ResourceKey(gvr)]
+}
+
+// ResourceKey returns the "resource.group" key used in the config file.
+// Core resources are keyed by their plain resource name.
+func ResourceKey(gvr schema.GroupVersionResource) string {
+	if gvr.Group == "" {
+		return gvr.Resource
+	}
+	return gvr.Resource + "." + gvr.Group
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Session is where the user left off in a kubeconfig context.
+type Session struct {
+	Workspace string `json:"workspace,omitempty"`
+	// View and Resource are set like in a Bookmark.
+	View      string   `json:"view,omitempty"`
+	Resource  string   `json:"resource,omitempty"`
+	Namespace string   `json:"namespace,omitempty"`
+	Selector  string   `json:"selector,omitempty"`
+	History   []string `json:"history,omitempty"`
+}
+
+// Sessions are the last sessions of all contexts, keyed by context name.
+type Sessions struct {
+	path     string
+	contexts map[string]Session
+}
+
+type sessionFile struct {
+	Contexts map[string]Session `json:"contexts"`
+}
+
+// SessionPath returns the file sessions are stored in, session.yaml next to
+// the config file.
+func (c *Config) SessionPath() string {
+	return filepath.Join(filepath.Dir(c.path), "session.yaml")
+}
+
+// LoadSessions reads the sessions file at path. A missing file yields no
+// sessions.
+func LoadSessions(path string) (*Sessions, error) {
+	s := &Sessions{path: path, contexts: make(map[string]Session)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sessions %s: %w", path, err)
+	}
+
+	var file sessionFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse sessions %s: %w", path, err)
+	}
+	for name, session := range file.Contexts {
+		s.contexts[name] = session
+	}
+	return s, nil
+}
+
+// Get returns the last session in context.
+func (s *Sessions) Get(context string) (Session, bool) {
+	session, ok := s.contexts[context]
+	return session, ok
+}
+
+// Set records the session of context. It is written by Save.
+func (s *Sessions) Set(context string, session Session) {
+	s.contexts[context] = session
+}
+
+// Save writes all sessions to the sessions file.
+func (s *Sessions) Save() error {
+	data, err := yaml.Marshal(sessionFile{Contexts: s.contexts})
+	if err != nil {
+		return fmt.Errorf("failed to encode sessions: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for sessions: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write sessions %s: %w", s.path, err)
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+package kcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"sigs.k8s.io/yaml"
+)
+
+// Manifest is a single object read from a manifest file.
+type Manifest struct {
+	Source string
+	Object *unstructured.Unstructured
+}
+
+// ApplyPlan is the result of server-side dry-running one manifest.
+type ApplyPlan struct {
+	Manifest Manifest
+	Ref      ObjectRef
+	// Diff is the unified diff between the live object and the dry-run result.
+	Diff string
+	// Create is true if the object does not exist yet.
+	Create bool
+	// Live is the object on the server at planning time, nil if Create.
+	Live *unstructured.Unstructured
+	// Deferred is true if the kind is not served yet, for instance because
+	// an earlier manifest defines it in a CRD or binds it with an
+	// APIBinding. The manifest is mapped again when it is applied.
+	Deferred bool
+	Err      error
+}
+
+// Action describes what applying the plan would do: create, update,
+// unchanged, deferred or error.
+func (p ApplyPlan) Action() string {
+	switch {
+	case p.Deferred:
+		return "deferred"
+	case p.Err != nil:
+		return "error"
+	case p.Create:
+		return "create"
+	case p.Diff == "":
+		return "unchanged"
+	default:
+		return "update"
+	}
+}
+
+// ReadManifests reads all objects from a YAML or JSON file, or from every
+// .yaml, .yml and .json file below a directory, in lexical order.
+func ReadManifests(path string) ([]Manifest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	if info.IsDir() {
+		err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(filepath.Ext(p)) {
+			case ".yaml", ".yml", ".json":
+				if !d.IsDir() {
+					files = append(files, p)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(files)
+	} else {
+		files = []string{path}
+	}
+
+	var manifests []Manifest
+	for _, file := range files {
+		objs, err := readManifestFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+		manifests = append(manifests, objs...)
+	}
+	return manifests, nil
+}
+
+func readManifestFile(file string) ([]Manifest, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var manifests []Manifest
+	decoder := utilyaml.NewYAMLOrJSONDecoder(f, 4096)
+	for {
+		var obj map[string]interface{}
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		if len(obj) == 0 {
+			continue
+		}
+
+		u := &unstructured.Unstructured{Object: obj}
+		if u.GetKind() == "" || u.GetAPIVersion() == "" {
+			return nil, fmt.Errorf("object without apiVersion or kind")
+		}
+		if u.GetName() == "" {
+			return nil, fmt.Errorf("%s without metadata.name", u.GetKind())
+		}
+		manifests = append(manifests, Manifest{Source: file, Object: u})
+	}
+	return manifests, nil
+}
+
+// manifestMapper resolves kinds to resources via discovery in a workspace.
+// Discovery is refreshed once when a kind is unknown, since APIs in kcp come
+// and go with APIBindings.
+type manifestMapper struct {
+	client    discovery.DiscoveryInterface
+	mapper    meta.RESTMapper
+	refreshed bool
+}
+
+func (c *ClientManager) newManifestMapper(workspace string) (*manifestMapper, error) {
+	cfg := rest.CopyConfig(c.RestConfig)
+	cfg.Host = c.workspaceHost(workspace)
+
+	client, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client for workspace %s: %w", workspace, err)
+	}
+	return &manifestMapper{client: client}, nil
+}
+
+func (m *manifestMapper) load() error {
+	groups, err := restmapper.GetAPIGroupResources(m.client)
+	if err != nil {
+		return fmt.Errorf("failed to discover resources: %w", err)
+	}
+	m.mapper = restmapper.NewDiscoveryRESTMapper(groups)
+	return nil
+}
+
+func (m *manifestMapper) mapping(obj *unstructured.Unstructured) (*meta.RESTMapping, error) {
+	if m.mapper == nil {
+		if err := m.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	gvk := obj.GroupVersionKind()
+	mapping, err := m.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) && !m.refreshed {
+		m.refreshed = true
+		if err := m.load(); err != nil {
+			return nil, err
+		}
+		mapping, err = m.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unknown kind %s in this workspace: %w", gvk, err)
+	}
+	return mapping, nil
+}
+
+func (m *manifestMapper) ref(workspace string, obj *unstructured.Unstructured) (ObjectRef, error) {
+	mapping, err := m.mapping(obj)
+	if err != nil {
+		return ObjectRef{}, err
+	}
+
+	ref := ObjectRef{Workspace: workspace, GVR: mapping.Resource, Name: obj.GetName()}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		ref.Namespace = obj.GetNamespace()
+		if ref.Namespace == "" {
+			ref.Namespace = metav1.NamespaceDefault
+			obj.SetNamespace(ref.Namespace)
+		}
+	}
+	return ref, nil
+}
+
+// PlanApply server-side dry-runs every manifest in workspace and diffs the
+// result against the live objects. Manifests of kinds that are not served
+// yet are deferred if earlier manifests may provide them.
+func (c *ClientManager) PlanApply(ctx context.Context, workspace string, manifests []Manifest) ([]ApplyPlan, error) {
+	mapper, err := c.newManifestMapper(workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]ApplyPlan, 0, len(manifests))
+	for i, manifest := range manifests {
+		plan := ApplyPlan{Manifest: manifest}
+		plan.Ref, plan.Err = mapper.ref(workspace, manifest.Object)
+		if meta.IsNoMatchError(plan.Err) && i > 0 {
+			plan.Ref = ObjectRef{Workspace: workspace, Name: manifest.Object.GetName()}
+			plan.Deferred = true
+		}
+		if plan.Err == nil {
+			plan.Live, plan.Diff, plan.Err = c.dryRunDiff(ctx, plan.Ref, manifest.Object)
+			plan.Create = plan.Live == nil
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// dryRunDiff returns the live object, nil if it does not exist, and the diff
+// a server-side apply of obj would make to it.
+func (c *ClientManager) dryRunDiff(ctx context.Context, ref ObjectRef, obj *unstructured.Unstructured) (*unstructured.Unstructured, string, error) {
+	var before []byte
+	live, err := c.GetResource(ctx, ref)
+	switch {
+	case apierrors.IsNotFound(err):
+		live = nil
+	case err != nil:
+		return nil, "", err
+	default:
+		before, err = diffableYAML(live.Object)
+		if err != nil {
+			return live, "", err
+		}
+	}
+
+	result, err := c.applyObject(ctx, ref, obj, true, false)
+	if err != nil {
+		return live, "", err
+	}
+	after, err := diffableYAML(result.Object)
+	if err != nil {
+		return live, "", err
+	}
+
+	return live, UnifiedDiff(before, after, "live", "applied"), nil
+}
+
+// diffableYAML renders an object without server-managed metadata so that
+// diffs only show meaningful changes.
+func diffableYAML(obj map[string]interface{}) ([]byte, error) {
+	u := (&unstructured.Unstructured{Object: obj}).DeepCopy()
+	u.SetManagedFields(nil)
+	u.SetResourceVersion("")
+	u.SetGeneration(0)
+	u.SetUID("")
+	u.SetCreationTimestamp(metav1.Time{})
+	unstructured.RemoveNestedField(u.Object, "status")
+	return yaml.Marshal(u.Object)
+}
+
+// kindServedTimeout is how long applying a deferred manifest waits for its
+// kind to be served after the manifests before it have been applied.
+const kindServedTimeout = 30 * time.Second
+
+// ApplyManifests server-side applies the planned manifests in order. Plans
+// that already failed are skipped. Deferred plans are mapped again once the
+// manifests before them have been applied, and their Ref is updated. The
+// returned errors match the plans.
+func (c *ClientManager) ApplyManifests(ctx context.Context, plans []ApplyPlan) []error {
+	errs := make([]error, len(plans))
+	var mapper *manifestMapper
+	applied := false
+	for i := range plans {
+		plan := &plans[i]
+		if plan.Deferred {
+			if !applied {
+				errs[i] = plan.Err
+				continue
+			}
+			if mapper == nil {
+				m, err := c.newManifestMapper(plan.Ref.Workspace)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				mapper = m
+			}
+			if errs[i] = c.resolveDeferred(ctx, mapper, plan); errs[i] != nil {
+				continue
+			}
+		} else if plan.Err != nil {
+			errs[i] = plan.Err
+			continue
+		}
+
+		_, errs[i] = c.ApplyResource(ctx, plan.Ref, plan.Live, plan.Manifest.Object)
+		if errs[i] == nil {
+			applied = true
+		}
+	}
+	return errs
+}
+
+// resolveDeferred maps a deferred plan, rediscovering the APIs of the
+// workspace until its kind is served or kindServedTimeout has passed.
+func (c *ClientManager) resolveDeferred(ctx context.Context, mapper *manifestMapper, plan *ApplyPlan) error {
+	deadline := time.Now().Add(kindServedTimeout)
+	for {
+		if err := mapper.load(); err != nil {
+			return err
+		}
+		mapper.refreshed = true
+
+		ref, err := mapper.ref(plan.Ref.Workspace, plan.Manifest.Object)
+		if err == nil {
+			live, err := c.GetResource(ctx, ref)
+			if err != nil && !apierrors.IsNotFound(err) {
+				return err
+			}
+			if err != nil {
+				live = nil
+			}
+			plan.Ref, plan.Live, plan.Err, plan.Deferred = ref, live, nil, false
+			return nil
+		}
+		if !meta.IsNoMatchError(err) || time.Now().After(deadline) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
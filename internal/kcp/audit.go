@@ -0,0 +1,127 @@
+package kcp
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/peter/kcplens/internal/audit"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// record appends a mutating request to the audit log, if one is configured.
+// Failed requests are recorded as well, together with their error. A failure
+// to write the log does not fail the request; it is kept by the log until
+// the UI takes it with TakeError.
+func (c *ClientManager) record(ref ObjectRef, verb string, dryRun bool, diff string, reqErr error) {
+	if c.auditLog == nil {
+		return
+	}
+
+	entry := audit.Entry{
+		Timestamp: time.Now(),
+		Context:   c.contextName,
+		User:      c.userName,
+		Workspace: ref.Workspace,
+		GVR:       ref.GVR.GroupVersion().String() + "/" + ref.GVR.Resource,
+		Namespace: ref.Namespace,
+		Name:      ref.Name,
+		Verb:      verb,
+		DryRun:    dryRun,
+		Diff:      diff,
+	}
+	if reqErr != nil {
+		entry.Error = reqErr.Error()
+	}
+	_ = c.auditLog.Append(entry)
+}
+
+// changeSummary summarizes how obj differs from live, the server state the
+// change is based on. A nil live object stands for one that does not exist
+// yet. It is only computed when auditing is enabled.
+func (c *ClientManager) changeSummary(live, obj *unstructured.Unstructured) string {
+	if c.auditLog == nil {
+		return ""
+	}
+
+	before := map[string]interface{}{}
+	if live != nil {
+		before = editable(live.Object)
+	}
+	return diffSummary(before, editable(obj.Object))
+}
+
+// editable drops the fields that are not part of a user's change.
+func editable(obj map[string]interface{}) map[string]interface{} {
+	u := (&unstructured.Unstructured{Object: obj}).DeepCopy()
+	u.SetManagedFields(nil)
+	u.SetResourceVersion("")
+	u.SetGeneration(0)
+	unstructured.RemoveNestedField(u.Object, "status")
+	return u.Object
+}
+
+// diffSummary counts the added and removed YAML lines between two objects
+// and names the changed fields down to the second level, e.g. "spec.size".
+func diffSummary(before, after map[string]interface{}) string {
+	var beforeYAML []byte
+	if len(before) > 0 {
+		beforeYAML, _ = yaml.Marshal(before)
+	}
+	afterYAML, _ := yaml.Marshal(after)
+
+	added, removed := 0, 0
+	for _, line := range strings.Split(UnifiedDiff(beforeYAML, afterYAML, "", ""), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			removed++
+		}
+	}
+	if added == 0 && removed == 0 {
+		return "no changes"
+	}
+
+	fields := changedFields(before, after, "", 2)
+	summary := fmt.Sprintf("+%d -%d lines", added, removed)
+	if len(fields) > 0 {
+		summary += " (" + strings.Join(fields, ", ") + ")"
+	}
+	return summary
+}
+
+func changedFields(before, after map[string]interface{}, prefix string, depth int) []string {
+	keys := make(map[string]bool)
+	for k := range before {
+		keys[k] = true
+	}
+	for k := range after {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var fields []string
+	for _, k := range sorted {
+		b, a := before[k], after[k]
+		if reflect.DeepEqual(b, a) {
+			continue
+		}
+		bm, bok := b.(map[string]interface{})
+		am, aok := a.(map[string]interface{})
+		if depth > 1 && bok && aok {
+			fields = append(fields, changedFields(bm, am, prefix+k+".", depth-1)...)
+			continue
+		}
+		fields = append(fields, prefix+k)
+	}
+	return fields
+}
@@ -0,0 +1,210 @@
+package kcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// APIExportRef describes an APIExport that can be bound, including the
+// permission claims a binding has to accept or reject.
+type APIExportRef struct {
+	Name    string
+	Path    string
+	Version string
+	Claims  []PermissionClaim
+}
+
+// PermissionClaim is a claim on resources in the consuming workspace that an
+// APIExport requests.
+type PermissionClaim struct {
+	Group        string
+	Resource     string
+	IdentityHash string
+	Verbs        []string
+	Raw          map[string]interface{}
+}
+
+func (p PermissionClaim) String() string {
+	name := p.Resource
+	if p.Group != "" {
+		name += "." + p.Group
+	}
+	if len(p.Verbs) > 0 {
+		return fmt.Sprintf("%s %v", name, p.Verbs)
+	}
+	return name
+}
+
+// NewAPIBinding describes an APIBinding to be created.
+type NewAPIBinding struct {
+	Name   string
+	Export APIExportRef
+	// Accepted holds one entry per export claim, true if the claim is accepted.
+	Accepted []bool
+}
+
+func apiBindingGVR(version string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: "apis.kcp.io", Version: version, Resource: "apibindings"}
+}
+
+func apiExportGVR(version string) schema.GroupVersionResource {
+	return schema.GroupVersionResource{Group: "apis.kcp.io", Version: version, Resource: "apiexports"}
+}
+
+// NewAPIExportRef builds an APIExportRef from a raw APIExport object.
+func NewAPIExportRef(path string, raw map[string]interface{}) APIExportRef {
+	u := unstructured.Unstructured{Object: raw}
+	gv, _ := schema.ParseGroupVersion(u.GetAPIVersion())
+
+	ref := APIExportRef{
+		Name:    u.GetName(),
+		Path:    path,
+		Version: gv.Version,
+	}
+
+	claims, _, _ := unstructured.NestedSlice(raw, "spec", "permissionClaims")
+	for _, c := range claims {
+		claim, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		pc := PermissionClaim{Raw: claim}
+		pc.Group, _, _ = unstructured.NestedString(claim, "group")
+		pc.Resource, _, _ = unstructured.NestedString(claim, "resource")
+		pc.IdentityHash, _, _ = unstructured.NestedString(claim, "identityHash")
+		pc.Verbs, _, _ = unstructured.NestedStringSlice(claim, "verbs")
+		ref.Claims = append(ref.Claims, pc)
+	}
+
+	return ref
+}
+
+// exportPath returns the path an APIBinding can reference export by: its
+// workspace path if known, else its logical cluster. It is empty if the
+// export carries neither.
+func exportPath(export GenericResource) string {
+	u := unstructured.Unstructured{Object: export.Raw}
+	annotations := u.GetAnnotations()
+	if path := annotations["kcp.io/path"]; path != "" {
+		return path
+	}
+	return annotations["kcp.io/cluster"]
+}
+
+// DiscoverAPIExportCatalog lists the APIExports of all workspaces through the
+// wildcard endpoint. Exports are identified by their workspace path, or by
+// their logical cluster if the path is unknown. Exports without either
+// cannot be bound and are left out.
+func (c *ClientManager) DiscoverAPIExportCatalog(ctx context.Context) ([]APIExportRef, error) {
+	var lastErr error
+	for _, version := range []string{"v1alpha2", "v1alpha1"} {
+		exports, err := c.DiscoverWildcardResources(ctx, apiExportGVR(version))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		refs := make([]APIExportRef, 0, len(exports))
+		for _, e := range exports {
+			path := exportPath(e)
+			if path == "" {
+				continue
+			}
+			refs = append(refs, NewAPIExportRef(path, e.Raw))
+		}
+		sort.Slice(refs, func(i, j int) bool {
+			if refs[i].Name != refs[j].Name {
+				return refs[i].Name < refs[j].Name
+			}
+			return refs[i].Path < refs[j].Path
+		})
+		return refs, nil
+	}
+	return nil, fmt.Errorf("failed to list APIExports: %w", lastErr)
+}
+
+// bindingClaim turns an export claim into the claim entry of an APIBinding.
+func bindingClaim(version string, claim PermissionClaim, accepted bool) map[string]interface{} {
+	out := make(map[string]interface{}, len(claim.Raw)+2)
+	for k, v := range claim.Raw {
+		out[k] = v
+	}
+
+	out["state"] = "Rejected"
+	if accepted {
+		out["state"] = "Accepted"
+	}
+
+	if version == "v1alpha1" {
+		if _, ok := out["resourceSelector"]; !ok {
+			out["all"] = true
+		}
+		return out
+	}
+	if _, ok := out["selector"]; !ok {
+		out["selector"] = map[string]interface{}{"matchAll": true}
+	}
+	return out
+}
+
+// CreateAPIBinding binds an APIExport into workspace.
+func (c *ClientManager) CreateAPIBinding(ctx context.Context, workspace string, b NewAPIBinding) (ObjectRef, error) {
+	version := b.Export.Version
+	if version == "" {
+		version = "v1alpha2"
+	}
+	gvr := apiBindingGVR(version)
+	ref := ObjectRef{Workspace: workspace, GVR: gvr, Name: b.Name}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(gvr.GroupVersion().String())
+	obj.SetKind("APIBinding")
+	obj.SetName(b.Name)
+
+	export := map[string]interface{}{"name": b.Export.Name}
+	if b.Export.Path != "" {
+		export["path"] = b.Export.Path
+	}
+	if err := unstructured.SetNestedMap(obj.Object, export, "spec", "reference", "export"); err != nil {
+		return ref, err
+	}
+
+	if len(b.Export.Claims) > 0 {
+		claims := make([]interface{}, len(b.Export.Claims))
+		for i, claim := range b.Export.Claims {
+			claims[i] = bindingClaim(version, claim, i < len(b.Accepted) && b.Accepted[i])
+		}
+		if err := unstructured.SetNestedSlice(obj.Object, claims, "spec", "permissionClaims"); err != nil {
+			return ref, err
+		}
+	}
+
+	if _, err := c.CreateResource(ctx, ref, obj); err != nil {
+		return ref, err
+	}
+	return ref, nil
+}
+
+// APIBindingStatus returns the status of an APIBinding and whether it is ready.
+func (c *ClientManager) APIBindingStatus(ctx context.Context, ref ObjectRef) (string, bool, error) {
+	obj, err := c.GetResource(ctx, ref)
+	if err != nil {
+		return "", false, err
+	}
+	return getStatus(*obj), isReady(*obj), nil
+}
+
+// isReady reports whether the Ready condition of an object is true.
+func isReady(u unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, cond := range conditions {
+		if c, ok := cond.(map[string]interface{}); ok && c["type"] == "Ready" {
+			return c["status"] == "True"
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package kcp
+
+import "testing"
+
+func TestExportPath(t *testing.T) {
+	tests := []struct {
+		annotations map[string]interface{}
+		want        string
+	}{
+		{annotations: map[string]interface{}{"kcp.io/path": "root:shop", "kcp.io/cluster": "2x8m"}, want: "root:shop"},
+		{annotations: map[string]interface{}{"kcp.io/cluster": "2x8m"}, want: "2x8m"},
+		{annotations: nil, want: ""},
+	}
+
+	for _, tt := range tests {
+		raw := map[string]interface{}{"metadata": map[string]interface{}{"name": "widgets"}}
+		if tt.annotations != nil {
+			raw["metadata"].(map[string]interface{})["annotations"] = tt.annotations
+		}
+		if got := exportPath(GenericResource{Raw: raw}); got != tt.want {
+			t.Errorf("exportPath(%v) = %q, want %q", tt.annotations, got, tt.want)
+		}
+	}
+}
@@ -1,27 +1,44 @@
 package kcp
 
 import (
+	"errors"
 	"fmt"
-	"os"
+	"sort"
 	"strings"
 
+	"github.com/peter/kcplens/internal/audit"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/tools/clientcmd/api"
 )
 
+// ErrReadOnly is returned by every write operation while read-only mode is enabled.
+var ErrReadOnly = errors.New("kcplens is in read-only mode")
+
 type ClientManager struct {
 	RestConfig      *rest.Config
 	Clientset       *kubernetes.Clientset
 	DynamicClient   dynamic.Interface
 	DiscoveryClient discovery.DiscoveryInterface
 	baseHost        string
+	kind            ClusterKind
+	probed          bool
+	// probeConfig is the config as loaded from the kubeconfig. It is never
+	// modified, so that Probe can run in the background.
+	probeConfig *rest.Config
+	conn        *connTracker
 
 	currentWorkspace string
+	initialWorkspace string
 	discoveryCache   map[string]interface{}
+	readOnly         bool
+
+	kubeconfigPath string
+	contextName    string
+	userName       string
+	auditLog       *audit.Log
 }
 
 func NewClientManager(kubeconfigPath string) (*ClientManager, error) {
@@ -30,81 +47,95 @@ func NewClientManager(kubeconfigPath string) (*ClientManager, error) {
 		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
 	}
 
-	baseHost := config.Host
-	if idx := strings.Index(config.Host, "/clusters/"); idx > 0 {
-		baseHost = config.Host[:idx]
-	}
-
-	config.Host = baseHost + "/clusters/root"
+	contextName, userName := kubeconfigIdentity(kubeconfigPath, "")
+	return newClientManager(config, kubeconfigPath, contextName, userName)
+}
 
-	clientset, err := kubernetes.NewForConfig(config)
+func NewClientManagerWithContext(kubeconfigPath, contextName string) (*ClientManager, error) {
+	config, err := BuildConfigFromContext(kubeconfigPath, contextName)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create clientset: %w", err)
+		return nil, fmt.Errorf("failed to load kubeconfig with context %s: %w", contextName, err)
 	}
 
-	dynamicClient, err := dynamic.NewForConfig(config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
-	}
+	_, userName := kubeconfigIdentity(kubeconfigPath, contextName)
+	return newClientManager(config, kubeconfigPath, contextName, userName)
+}
 
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create discovery client: %w", err)
-	}
+// newClientManager creates the clients for the workspace in the URL of
+// config. The server is treated as kcp until SetKind records what Probe
+// found.
+func newClientManager(config *rest.Config, kubeconfigPath, contextName, userName string) (*ClientManager, error) {
+	baseHost, workspace := splitClusterURL(config.Host)
 
-	return &ClientManager{
+	c := &ClientManager{
 		RestConfig:       config,
-		Clientset:        clientset,
-		DynamicClient:    dynamicClient,
-		DiscoveryClient:  discoveryClient,
+		probeConfig:      rest.CopyConfig(config),
 		baseHost:         baseHost,
-		currentWorkspace: "root",
+		kind:             ClusterUnknown,
+		conn:             &connTracker{},
+		currentWorkspace: workspace,
+		initialWorkspace: workspace,
 		discoveryCache:   make(map[string]interface{}),
-	}, nil
+		kubeconfigPath:   kubeconfigPath,
+		contextName:      contextName,
+		userName:         userName,
+	}
+	config.Host = c.workspaceHost(workspace)
+	config.Wrap(c.conn.wrap)
+
+	if err := c.buildClients(); err != nil {
+		return nil, err
+	}
+	return c, nil
 }
 
-func NewClientManagerWithContext(kubeconfigPath, contextName string) (*ClientManager, error) {
-	config, err := BuildConfigFromContext(kubeconfigPath, contextName)
+// buildClients creates all clients for the current RestConfig.
+func (c *ClientManager) buildClients() error {
+	var err error
+	c.Clientset, err = kubernetes.NewForConfig(c.RestConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load kubeconfig with context %s: %w", contextName, err)
+		return fmt.Errorf("failed to create clientset: %w", err)
 	}
 
-	baseHost := config.Host
-	if idx := strings.Index(config.Host, "/clusters/"); idx > 0 {
-		baseHost = config.Host[:idx]
+	c.DynamicClient, err = dynamic.NewForConfig(c.RestConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
 	}
 
-	config.Host = baseHost + "/clusters/root"
-
-	clientset, err := kubernetes.NewForConfig(config)
+	c.DiscoveryClient, err = discovery.NewDiscoveryClientForConfig(c.RestConfig)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create clientset: %w", err)
+		return fmt.Errorf("failed to create discovery client: %w", err)
 	}
+	return nil
+}
 
-	dynamicClient, err := dynamic.NewForConfig(config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+// splitClusterURL splits a kcp server URL into the base URL and the
+// workspace selected with /clusters/<path>, as written by kubectl ws.
+// URLs without a workspace select root.
+func splitClusterURL(host string) (string, string) {
+	idx := strings.Index(host, "/clusters/")
+	if idx < 0 {
+		return strings.TrimSuffix(host, "/"), "root"
 	}
 
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	workspace := host[idx+len("/clusters/"):]
+	if end := strings.Index(workspace, "/"); end >= 0 {
+		workspace = workspace[:end]
 	}
+	if workspace == "" || workspace == "*" {
+		workspace = "root"
+	}
+	return host[:idx], workspace
+}
 
-	return &ClientManager{
-		RestConfig:       config,
-		Clientset:        clientset,
-		DynamicClient:    dynamicClient,
-		DiscoveryClient:  discoveryClient,
-		baseHost:         baseHost,
-		currentWorkspace: "root",
-		discoveryCache:   make(map[string]interface{}),
-	}, nil
+// InitialWorkspace returns the workspace selected in the kubeconfig.
+func (c *ClientManager) InitialWorkspace() string {
+	return c.initialWorkspace
 }
 
 func (c *ClientManager) SwitchWorkspace(path string) error {
 	c.currentWorkspace = path
-	c.RestConfig.Host = c.baseHost + "/clusters/" + path
+	c.RestConfig.Host = c.workspaceHost(path)
 
 	var err error
 	c.DynamicClient, err = dynamic.NewForConfig(c.RestConfig)
@@ -121,10 +152,69 @@ func (c *ClientManager) SwitchWorkspace(path string) error {
 	return nil
 }
 
+// Clone returns a client for the same server, context and settings that
+// can switch workspaces independently of c.
+func (c *ClientManager) Clone() (*ClientManager, error) {
+	clone := *c
+	clone.RestConfig = rest.CopyConfig(c.RestConfig)
+	if err := clone.SwitchWorkspace(c.currentWorkspace); err != nil {
+		return nil, err
+	}
+	return &clone, nil
+}
+
+// InvalidateCache drops the cached workspace listing for path.
+func (c *ClientManager) InvalidateCache(path string) {
+	delete(c.discoveryCache, path)
+}
+
 func (c *ClientManager) SetWorkspace(path string) {
 	c.SwitchWorkspace(path)
 }
 
+// SetReadOnly enables or disables read-only mode. In read-only mode all
+// operations that would change objects on the server fail with ErrReadOnly.
+func (c *ClientManager) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
+func (c *ClientManager) ReadOnly() bool {
+	return c.readOnly
+}
+
+// SetAuditLog makes the client record every mutating request in log.
+func (c *ClientManager) SetAuditLog(log *audit.Log) {
+	c.auditLog = log
+}
+
+// AuditLog returns the audit log the client records to, if any.
+func (c *ClientManager) AuditLog() *audit.Log {
+	return c.auditLog
+}
+
+// CopySettings applies the read-only mode and audit log of other, used when
+// a new client replaces an existing one.
+func (c *ClientManager) CopySettings(other *ClientManager) {
+	c.readOnly = other.readOnly
+	c.auditLog = other.auditLog
+}
+
+// KubeconfigPath returns the kubeconfig path the client was created from.
+// An empty path means the default loading rules.
+func (c *ClientManager) KubeconfigPath() string {
+	return c.kubeconfigPath
+}
+
+// ContextName returns the kubeconfig context the client was built from.
+func (c *ClientManager) ContextName() string {
+	return c.contextName
+}
+
+// UserName returns the kubeconfig user of the client's context.
+func (c *ClientManager) UserName() string {
+	return c.userName
+}
+
 func (c *ClientManager) CurrentWorkspace() string {
 	return c.currentWorkspace
 }
@@ -133,69 +223,77 @@ func (c *ClientManager) BaseHost() string {
 	return c.baseHost
 }
 
-func loadKubeConfig(kubeconfigPath string) (*rest.Config, error) {
-	if kubeconfigPath == "" {
-		kubeconfigPath = os.Getenv("KUBECONFIG")
-	}
-	if kubeconfigPath == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, err
-		}
-		kubeconfigPath = home + "/.kube/config"
-	}
-
-	return clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+// ContextInfo describes a kubeconfig context.
+type ContextInfo struct {
+	Name      string
+	Cluster   string
+	Server    string
+	User      string
+	Namespace string
+	Current   bool
 }
 
-func GetContexts(kubeconfigPath string) ([]string, string, error) {
-	if kubeconfigPath == "" {
-		kubeconfigPath = os.Getenv("KUBECONFIG")
-	}
-	if kubeconfigPath == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, "", err
-		}
-		kubeconfigPath = home + "/.kube/config"
-	}
-
+// kubeconfigLoadingRules returns the standard kubeconfig loading rules: an
+// explicit path if given, otherwise the files listed in KUBECONFIG, merged
+// like kubectl does, or ~/.kube/config.
+func kubeconfigLoadingRules(kubeconfigPath string) *clientcmd.ClientConfigLoadingRules {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	loadingRules.ExplicitPath = kubeconfigPath
+	return loadingRules
+}
 
-	config, err := loadingRules.Load()
+func loadKubeConfig(kubeconfigPath string) (*rest.Config, error) {
+	return BuildConfigFromContext(kubeconfigPath, "")
+}
+
+// GetContexts returns all contexts of the kubeconfig sorted by name, and the
+// name of the current context.
+func GetContexts(kubeconfigPath string) ([]ContextInfo, string, error) {
+	config, err := kubeconfigLoadingRules(kubeconfigPath).Load()
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to load kubeconfig: %w", err)
 	}
 
-	contexts := make([]string, 0, len(config.Contexts))
-	for name := range config.Contexts {
-		contexts = append(contexts, name)
+	contexts := make([]ContextInfo, 0, len(config.Contexts))
+	for name, ctx := range config.Contexts {
+		info := ContextInfo{
+			Name:      name,
+			Cluster:   ctx.Cluster,
+			User:      ctx.AuthInfo,
+			Namespace: ctx.Namespace,
+			Current:   name == config.CurrentContext,
+		}
+		if cluster, ok := config.Clusters[ctx.Cluster]; ok {
+			info.Server = cluster.Server
+		}
+		contexts = append(contexts, info)
 	}
+	sort.Slice(contexts, func(i, j int) bool {
+		return contexts[i].Name < contexts[j].Name
+	})
 
 	return contexts, config.CurrentContext, nil
 }
 
-func BuildConfigFromContext(kubeconfigPath, contextName string) (*rest.Config, error) {
-	if kubeconfigPath == "" {
-		kubeconfigPath = os.Getenv("KUBECONFIG")
+// kubeconfigIdentity returns the context and user names a kubeconfig
+// resolves to. An empty contextName selects the current context.
+func kubeconfigIdentity(kubeconfigPath, contextName string) (string, string) {
+	config, err := kubeconfigLoadingRules(kubeconfigPath).Load()
+	if err != nil {
+		return contextName, ""
 	}
-	if kubeconfigPath == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			return nil, err
-		}
-		kubeconfigPath = home + "/.kube/config"
+	if contextName == "" {
+		contextName = config.CurrentContext
 	}
-
-	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
-	loadingRules.ExplicitPath = kubeconfigPath
-
-	overrides := &clientcmd.ConfigOverrides{
-		ClusterInfo: api.Cluster{},
-		Context:     api.Context{},
+	if ctx, ok := config.Contexts[contextName]; ok {
+		return contextName, ctx.AuthInfo
 	}
-	overrides.CurrentContext = contextName
+	return contextName, ""
+}
 
-	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+// BuildConfigFromContext builds a client config for a context. An empty
+// contextName selects the current context.
+func BuildConfigFromContext(kubeconfigPath, contextName string) (*rest.Config, error) {
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: contextName}
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(kubeconfigLoadingRules(kubeconfigPath), overrides).ClientConfig()
 }
@@ -0,0 +1,42 @@
+package kcp
+
+import (
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestSetKind(t *testing.T) {
+	tests := []struct {
+		host          string
+		kind          ClusterKind
+		wantBase      string
+		wantWorkspace string
+		wantHost      string
+	}{
+		{host: "https://kcp:6443/clusters/root:shop", kind: ClusterKCP, wantBase: "https://kcp:6443", wantWorkspace: "root:shop", wantHost: "https://kcp:6443/clusters/root:shop"},
+		{host: "https://kcp:6443/clusters/root:shop", kind: ClusterUnknown, wantBase: "https://kcp:6443", wantWorkspace: "root:shop", wantHost: "https://kcp:6443/clusters/root:shop"},
+		{host: "https://k8s:6443/", kind: ClusterKubernetes, wantBase: "https://k8s:6443", wantWorkspace: "", wantHost: "https://k8s:6443"},
+	}
+
+	for _, tt := range tests {
+		c, err := newClientManager(&rest.Config{Host: tt.host}, "", "test", "")
+		if err != nil {
+			t.Fatalf("newClientManager(%s) error = %v", tt.host, err)
+		}
+		if c.Probed() {
+			t.Errorf("%s: client is probed before SetKind", tt.host)
+		}
+		if err := c.SetKind(tt.kind); err != nil {
+			t.Fatalf("SetKind(%s) error = %v", tt.kind, err)
+		}
+
+		if !c.Probed() || c.Kind() != tt.kind {
+			t.Errorf("%s: Probed() = %v, Kind() = %s, want true, %s", tt.host, c.Probed(), c.Kind(), tt.kind)
+		}
+		if c.BaseHost() != tt.wantBase || c.CurrentWorkspace() != tt.wantWorkspace || c.RestConfig.Host != tt.wantHost {
+			t.Errorf("%s as %s: base %q, workspace %q, host %q, want %q, %q, %q", tt.host, tt.kind,
+				c.BaseHost(), c.CurrentWorkspace(), c.RestConfig.Host, tt.wantBase, tt.wantWorkspace, tt.wantHost)
+		}
+	}
+}
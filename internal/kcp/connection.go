@@ -0,0 +1,79 @@
+package kcp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConnectionStatus describes the outcome of the most recent request to the
+// server.
+type ConnectionStatus struct {
+	// Latency is the duration of the last successful request.
+	Latency time.Duration
+	// LastContact is when the server last answered.
+	LastContact time.Time
+	// Err is set if the last request failed to reach the server or the
+	// server failed with a 5xx status.
+	Err error
+}
+
+// connTracker records the ConnectionStatus of all requests made with a
+// config it wraps.
+type connTracker struct {
+	mu     sync.Mutex
+	status ConnectionStatus
+}
+
+func (t *connTracker) wrap(rt http.RoundTripper) http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := time.Now()
+		resp, err := rt.RoundTrip(req)
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		switch {
+		case err != nil:
+			t.status.Err = err
+		case resp.StatusCode >= http.StatusInternalServerError:
+			t.status.Err = fmt.Errorf("server responded with %s", resp.Status)
+		default:
+			t.status.Err = nil
+			t.status.Latency = time.Since(start)
+			t.status.LastContact = time.Now()
+		}
+		return resp, err
+	})
+}
+
+func (t *connTracker) get() ConnectionStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// ConnectionStatus returns the outcome of the most recent request.
+func (c *ClientManager) ConnectionStatus() ConnectionStatus {
+	return c.conn.get()
+}
+
+// WhoAmI returns the name the server authenticates the user as, using a
+// SelfSubjectReview like kubectl auth whoami.
+func (c *ClientManager) WhoAmI(ctx context.Context) (string, error) {
+	review, err := c.Clientset.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to review own user: %w", err)
+	}
+	return review.Status.UserInfo.Username, nil
+}
@@ -3,20 +3,59 @@ package kcp
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"strings"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 )
 
+// versionPattern matches Kubernetes API versions like v1 or v1alpha2.
+var versionPattern = regexp.MustCompile(`^v[0-9]+((alpha|beta)[0-9]+)?$`)
+
+// WorkspaceGVR is the resource used to list and manage workspaces.
+var WorkspaceGVR = schema.GroupVersionResource{
+	Group:    "tenancy.kcp.io",
+	Version:  "v1alpha1",
+	Resource: "workspaces",
+}
+
+// WorkspaceTypeGVR is the resource describing the available workspace types.
+var WorkspaceTypeGVR = schema.GroupVersionResource{
+	Group:    "tenancy.kcp.io",
+	Version:  "v1alpha1",
+	Resource: "workspacetypes",
+}
+
+// WorkspaceTypeRef references a WorkspaceType by name and the workspace it lives in.
+type WorkspaceTypeRef struct {
+	Name string
+	Path string
+}
+
 type AvailableResource struct {
-	GVR        schema.GroupVersionResource
-	Kind       string
-	Namespaced bool
-	Count      int
+	GVR          schema.GroupVersionResource
+	Kind         string
+	SingularName string
+	ShortNames   []string
+	Categories   []string
+	Namespaced   bool
+	Count        int
+}
+
+// Aliases returns the names the resource type can be referred to by, like
+// kubectl accepts them: the plural and singular name and the short names.
+func (r AvailableResource) Aliases() []string {
+	aliases := []string{r.GVR.Resource}
+	if r.SingularName != "" && r.SingularName != r.GVR.Resource {
+		aliases = append(aliases, r.SingularName)
+	}
+	return append(aliases, r.ShortNames...)
 }
 
 type APIRelationship struct {
@@ -27,6 +66,9 @@ type APIRelationship struct {
 	ExportPath    string // For bindings: the workspace path of the export
 	ResourceName  string // For exports: the resource being exported
 	ResourceGroup string
+	GVR           schema.GroupVersionResource
+	Finalizers    []string
+	Deleting      bool                   // Set once a deletion timestamp exists
 	Raw           map[string]interface{} // Raw object for YAML display
 }
 
@@ -34,24 +76,42 @@ type SyncTarget struct {
 	Name   string
 	Status string
 	Labels map[string]string
+	Raw    map[string]interface{}
 }
 
 type GenericResource struct {
-	Name      string
-	Namespace string
-	Kind      string
-	Workspace string
-	Raw       map[string]interface{}
+	Name       string
+	Namespace  string
+	Kind       string
+	Workspace  string
+	Finalizers []string
+	Deleting   bool
+	Raw        map[string]interface{}
 }
 
 type WorkspaceNode struct {
-	Name     string
-	Path     string
-	Children []*WorkspaceNode
+	Name       string
+	Path       string
+	Phase      string
+	Finalizers []string
+	Deleting   bool
+	Raw        map[string]interface{}
+	Children   []*WorkspaceNode
+	// ListErr is set by DiscoverWorkspaceTree when the children of the
+	// workspace could not be listed.
+	ListErr error
+}
+
+// Inaccessible reports whether listing the children was denied.
+func (n *WorkspaceNode) Inaccessible() bool {
+	return IsAccessDenied(n.ListErr)
 }
 
 // DiscoverWorkspaces lists workspaces under a given path, using cache if available.
 func (c *ClientManager) DiscoverWorkspaces(ctx context.Context, parentPath string) ([]*WorkspaceNode, error) {
+	if !c.IsKCP() {
+		return nil, ErrNotKCP
+	}
 	if parentPath == "" {
 		parentPath = "root"
 	}
@@ -68,13 +128,7 @@ func (c *ClientManager) DiscoverWorkspaces(ctx context.Context, parentPath strin
 		return nil, fmt.Errorf("failed to switch to workspace %s: %w", parentPath, err)
 	}
 
-	gvr := schema.GroupVersionResource{
-		Group:    "tenancy.kcp.io",
-		Version:  "v1alpha1",
-		Resource: "workspaces",
-	}
-
-	workspaceList, err := c.DynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+	workspaceList, err := c.DynamicClient.Resource(WorkspaceGVR).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list workspaces in %s: %w", parentPath, err)
 	}
@@ -82,8 +136,12 @@ func (c *ClientManager) DiscoverWorkspaces(ctx context.Context, parentPath strin
 	var nodes []*WorkspaceNode
 	for _, ws := range workspaceList.Items {
 		nodes = append(nodes, &WorkspaceNode{
-			Name: ws.GetName(),
-			Path: parentPath + ":" + ws.GetName(),
+			Name:       ws.GetName(),
+			Path:       parentPath + ":" + ws.GetName(),
+			Phase:      getStatus(ws),
+			Finalizers: ws.GetFinalizers(),
+			Deleting:   ws.GetDeletionTimestamp() != nil,
+			Raw:        ws.Object,
 		})
 	}
 
@@ -93,6 +151,117 @@ func (c *ClientManager) DiscoverWorkspaces(ctx context.Context, parentPath strin
 	return nodes, nil
 }
 
+// DiscoverWorkspaceTree lists the workspace at path together with all
+// workspaces below it. Only failing to list path itself is an error;
+// workspaces below it whose children cannot be listed have ListErr set.
+func (c *ClientManager) DiscoverWorkspaceTree(ctx context.Context, path string) (*WorkspaceNode, error) {
+	node := &WorkspaceNode{Name: path[strings.LastIndex(path, ":")+1:], Path: path}
+	children, err := c.DiscoverWorkspaces(ctx, node.Path)
+	if err != nil {
+		return nil, err
+	}
+	node.Children = children
+	c.discoverChildren(ctx, children)
+	return node, nil
+}
+
+func (c *ClientManager) discoverChildren(ctx context.Context, nodes []*WorkspaceNode) {
+	for _, node := range nodes {
+		// Workspaces that are not ready yet or being deleted cannot be listed.
+		if node.Phase != "Ready" || node.Deleting {
+			continue
+		}
+		children, err := c.DiscoverWorkspaces(ctx, node.Path)
+		if err != nil {
+			node.ListErr = err
+			continue
+		}
+		node.Children = children
+		c.discoverChildren(ctx, children)
+	}
+}
+
+// CanListWorkspaces reports whether the user may list the workspaces in path,
+// which is what navigating into it requires.
+func (c *ClientManager) CanListWorkspaces(ctx context.Context, path string) (bool, error) {
+	rc, err := c.resourceClient(ObjectRef{Workspace: path, GVR: WorkspaceGVR})
+	if err != nil {
+		return false, err
+	}
+
+	_, err = rc.List(ctx, metav1.ListOptions{Limit: 1})
+	if IsAccessDenied(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to list workspaces in %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// WorkspaceExists reports whether the workspace at path exists by getting it
+// in its parent. A workspace the user may not get is assumed to exist, only
+// NotFound reports false.
+func (c *ClientManager) WorkspaceExists(ctx context.Context, path string) (bool, error) {
+	if path == "root" {
+		return true, nil
+	}
+
+	name := path[strings.LastIndex(path, ":")+1:]
+	rc, err := c.resourceClient(ObjectRef{Workspace: ParentPath(path), GVR: WorkspaceGVR, Name: name})
+	if err != nil {
+		return false, err
+	}
+
+	_, err = rc.Get(ctx, name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return false, nil
+	case IsAccessDenied(err):
+		return true, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to get workspace %s: %w", path, err)
+	}
+	return true, nil
+}
+
+// ListChildWorkspaces lists the workspaces in path like DiscoverWorkspaces,
+// but neither switches the current workspace nor uses the cache, so that it
+// can run in the background.
+func (c *ClientManager) ListChildWorkspaces(ctx context.Context, path string) ([]*WorkspaceNode, error) {
+	if !c.IsKCP() {
+		return nil, ErrNotKCP
+	}
+
+	rc, err := c.resourceClient(ObjectRef{Workspace: path, GVR: WorkspaceGVR})
+	if err != nil {
+		return nil, err
+	}
+
+	workspaceList, err := rc.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces in %s: %w", path, err)
+	}
+
+	nodes := make([]*WorkspaceNode, 0, len(workspaceList.Items))
+	for _, ws := range workspaceList.Items {
+		nodes = append(nodes, &WorkspaceNode{
+			Name:       ws.GetName(),
+			Path:       path + ":" + ws.GetName(),
+			Phase:      getStatus(ws),
+			Finalizers: ws.GetFinalizers(),
+			Deleting:   ws.GetDeletionTimestamp() != nil,
+			Raw:        ws.Object,
+		})
+	}
+	return nodes, nil
+}
+
+// IsAccessDenied reports whether err is the server refusing access.
+func IsAccessDenied(err error) bool {
+	return apierrors.IsForbidden(err) || apierrors.IsUnauthorized(err)
+}
+
 // DiscoverRootWorkspaces is a convenience wrapper for root discovery.
 func (c *ClientManager) DiscoverRootWorkspaces(ctx context.Context) ([]*WorkspaceNode, error) {
 	return c.DiscoverWorkspaces(ctx, "root")
@@ -112,6 +281,69 @@ func ParentPath(path string) string {
 	return path[:idx]
 }
 
+// workspaceNamePattern matches a single segment of a workspace path.
+var workspaceNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// ValidateWorkspacePath checks that path is a well-formed workspace path
+// below root, like root:org-one:team-alpha.
+func ValidateWorkspacePath(path string) error {
+	segments := strings.Split(path, ":")
+	if segments[0] != "root" {
+		return fmt.Errorf("invalid workspace path %q: must start with root", path)
+	}
+	for _, s := range segments[1:] {
+		if !workspaceNamePattern.MatchString(s) {
+			return fmt.Errorf("invalid workspace path %q: %q is not a valid workspace name", path, s)
+		}
+	}
+	return nil
+}
+
+// Ancestors returns the paths from root down to the parent of path.
+func Ancestors(path string) []string {
+	segments := strings.Split(path, ":")
+	ancestors := make([]string, 0, len(segments)-1)
+	for i := 1; i < len(segments); i++ {
+		ancestors = append(ancestors, strings.Join(segments[:i], ":"))
+	}
+	return ancestors
+}
+
+// DiscoverWorkspaceTypes lists the WorkspaceTypes visible from a workspace,
+// looking in the workspace itself and all of its ancestors. Types defined
+// closer to the workspace shadow those with the same name further up.
+func (c *ClientManager) DiscoverWorkspaceTypes(ctx context.Context, path string) ([]WorkspaceTypeRef, error) {
+	var types []WorkspaceTypeRef
+	seen := make(map[string]bool)
+
+	for p := path; ; p = ParentPath(p) {
+		rc, err := c.resourceClient(ObjectRef{Workspace: p, GVR: WorkspaceTypeGVR})
+		if err != nil {
+			return nil, err
+		}
+
+		list, err := rc.List(ctx, metav1.ListOptions{})
+		if err != nil && p == path {
+			return nil, fmt.Errorf("failed to list workspace types in %s: %w", p, err)
+		}
+		if err == nil {
+			for _, item := range list.Items {
+				if seen[item.GetName()] {
+					continue
+				}
+				seen[item.GetName()] = true
+				types = append(types, WorkspaceTypeRef{Name: item.GetName(), Path: p})
+			}
+		}
+
+		if IsRoot(p) {
+			break
+		}
+	}
+
+	return types, nil
+}
+
 // DiscoverAPIRelationships lists APIExports and APIBindings in the current workspace.
 func (c *ClientManager) DiscoverAPIRelationships(ctx context.Context, path string) ([]APIRelationship, error) {
 	if err := c.SwitchWorkspace(path); err != nil {
@@ -130,10 +362,13 @@ func (c *ClientManager) DiscoverAPIRelationships(ctx context.Context, path strin
 		if err == nil && len(exports.Items) > 0 {
 			for _, item := range exports.Items {
 				rel := APIRelationship{
-					Name:   item.GetName(),
-					Type:   "Export",
-					Status: getStatus(item),
-					Raw:    item.Object,
+					Name:       item.GetName(),
+					Type:       "Export",
+					Status:     getStatus(item),
+					GVR:        exportGVR,
+					Finalizers: item.GetFinalizers(),
+					Deleting:   item.GetDeletionTimestamp() != nil,
+					Raw:        item.Object,
 				}
 				if spec, ok := item.Object["spec"].(map[string]interface{}); ok {
 					if resources, ok := spec["resources"].([]interface{}); ok && len(resources) > 0 {
@@ -162,10 +397,13 @@ func (c *ClientManager) DiscoverAPIRelationships(ctx context.Context, path strin
 		if len(bindings.Items) > 0 {
 			for _, item := range bindings.Items {
 				rel := APIRelationship{
-					Name:   item.GetName(),
-					Type:   "Binding",
-					Status: getStatus(item),
-					Raw:    item.Object,
+					Name:       item.GetName(),
+					Type:       "Binding",
+					Status:     getStatus(item),
+					GVR:        bindingGVR,
+					Finalizers: item.GetFinalizers(),
+					Deleting:   item.GetDeletionTimestamp() != nil,
+					Raw:        item.Object,
 				}
 				if spec, ok := item.Object["spec"].(map[string]interface{}); ok {
 					if ref, ok := spec["reference"].(map[string]interface{}); ok {
@@ -246,6 +484,7 @@ func (c *ClientManager) DiscoverSyncTargets(ctx context.Context, path string) ([
 			Name:   item.GetName(),
 			Status: getStatus(item),
 			Labels: item.GetLabels(),
+			Raw:    item.Object,
 		})
 	}
 
@@ -266,11 +505,13 @@ func (c *ClientManager) DiscoverResources(ctx context.Context, path string, gvr
 	var resources []GenericResource
 	for _, item := range list.Items {
 		resources = append(resources, GenericResource{
-			Name:      item.GetName(),
-			Namespace: item.GetNamespace(),
-			Kind:      item.GetKind(),
-			Workspace: path,
-			Raw:       item.Object,
+			Name:       item.GetName(),
+			Namespace:  item.GetNamespace(),
+			Kind:       item.GetKind(),
+			Workspace:  path,
+			Finalizers: item.GetFinalizers(),
+			Deleting:   item.GetDeletionTimestamp() != nil,
+			Raw:        item.Object,
 		})
 	}
 
@@ -279,6 +520,10 @@ func (c *ClientManager) DiscoverResources(ctx context.Context, path string, gvr
 
 // DiscoverWildcardResources lists resources across all workspaces using clusters/*.
 func (c *ClientManager) DiscoverWildcardResources(ctx context.Context, gvr schema.GroupVersionResource) ([]GenericResource, error) {
+	if !c.IsKCP() {
+		return nil, ErrNotKCP
+	}
+
 	savedWorkspace := c.currentWorkspace
 
 	wildcardHost := c.baseHost + "/clusters/*"
@@ -304,11 +549,13 @@ func (c *ClientManager) DiscoverWildcardResources(ctx context.Context, gvr schem
 		}
 
 		resources = append(resources, GenericResource{
-			Name:      item.GetName(),
-			Namespace: item.GetNamespace(),
-			Kind:      item.GetKind(),
-			Workspace: ws,
-			Raw:       item.Object,
+			Name:       item.GetName(),
+			Namespace:  item.GetNamespace(),
+			Kind:       item.GetKind(),
+			Workspace:  ws,
+			Finalizers: item.GetFinalizers(),
+			Deleting:   item.GetDeletionTimestamp() != nil,
+			Raw:        item.Object,
 		})
 	}
 
@@ -322,8 +569,25 @@ func (c *ClientManager) DiscoverAvailableResources(ctx context.Context, path str
 	if err := c.SwitchWorkspace(path); err != nil {
 		return nil, err
 	}
+	return availableResources(c.DiscoveryClient)
+}
+
+// AvailableResourcesIn lists the resource types of a workspace like
+// DiscoverAvailableResources, but without switching the current workspace,
+// so that it can run in the background.
+func (c *ClientManager) AvailableResourcesIn(ctx context.Context, path string) ([]AvailableResource, error) {
+	cfg := rest.CopyConfig(c.RestConfig)
+	cfg.Host = c.workspaceHost(path)
+
+	client, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client for workspace %s: %w", path, err)
+	}
+	return availableResources(client)
+}
 
-	apiResourceLists, err := c.DiscoveryClient.ServerPreferredResources()
+func availableResources(client discovery.DiscoveryInterface) ([]AvailableResource, error) {
+	apiResourceLists, err := client.ServerPreferredResources()
 	if err != nil {
 		return nil, fmt.Errorf("failed to discover resources: %w", err)
 	}
@@ -354,10 +618,13 @@ func (c *ClientManager) DiscoverAvailableResources(ctx context.Context, path str
 			}
 
 			available = append(available, AvailableResource{
-				GVR:        gvr,
-				Kind:       r.Kind,
-				Namespaced: r.Namespaced,
-				Count:      -1,
+				GVR:          gvr,
+				Kind:         r.Kind,
+				SingularName: r.SingularName,
+				ShortNames:   r.ShortNames,
+				Categories:   r.Categories,
+				Namespaced:   r.Namespaced,
+				Count:        -1,
 			})
 		}
 	}
@@ -365,13 +632,57 @@ func (c *ClientManager) DiscoverAvailableResources(ctx context.Context, path str
 	return available, nil
 }
 
-// DiscoverResourcesInWorkspace lists resources of a specific type in a workspace.
-func (c *ClientManager) DiscoverResourcesInWorkspace(ctx context.Context, path string, gvr schema.GroupVersionResource, namespace string) ([]GenericResource, error) {
+// ResolveResource finds the resource type named by arg in a workspace. arg
+// may be a resource name ("widgets"), a kind ("Widget"), a singular or short
+// name ("widget", "ns"), or be qualified
+// with the group ("widgets.example.kcp.io") or version and group
+// ("widgets.v1.example.kcp.io"). A fully qualified resource that discovery
+// does not know is returned as is, so wildcard queries work for APIs that
+// are not available in the workspace itself.
+func (c *ClientManager) ResolveResource(ctx context.Context, path, arg string) (schema.GroupVersionResource, error) {
+	available, err := c.DiscoverAvailableResources(ctx, path)
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	return MatchResource(available, path, arg)
+}
+
+// MatchResource is ResolveResource for an already discovered list of
+// resources in the workspace at path.
+func MatchResource(available []AvailableResource, path, arg string) (schema.GroupVersionResource, error) {
+	fullySpecified, groupResource := schema.ParseResourceArg(strings.ToLower(arg))
+	for _, r := range available {
+		if fullySpecified != nil && r.GVR == *fullySpecified {
+			return r.GVR, nil
+		}
+	}
+	for _, r := range available {
+		if r.GVR.GroupResource() == groupResource || r.GVR.Resource == arg || strings.EqualFold(r.Kind, arg) {
+			return r.GVR, nil
+		}
+	}
+	for _, r := range available {
+		for _, alias := range r.Aliases() {
+			if strings.EqualFold(alias, arg) {
+				return r.GVR, nil
+			}
+		}
+	}
+
+	if fullySpecified != nil && versionPattern.MatchString(fullySpecified.Version) {
+		return *fullySpecified, nil
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("resource type %q not found in workspace %s", arg, path)
+}
+
+// DiscoverResourcesInWorkspace lists resources of a specific type in a
+// workspace, optionally limited to a namespace and a label selector.
+func (c *ClientManager) DiscoverResourcesInWorkspace(ctx context.Context, path string, gvr schema.GroupVersionResource, namespace, selector string) ([]GenericResource, error) {
 	if err := c.SwitchWorkspace(path); err != nil {
 		return nil, err
 	}
 
-	opts := metav1.ListOptions{}
+	opts := metav1.ListOptions{LabelSelector: selector}
 	var list *unstructured.UnstructuredList
 	var err error
 
@@ -387,11 +698,13 @@ func (c *ClientManager) DiscoverResourcesInWorkspace(ctx context.Context, path s
 	var resources []GenericResource
 	for _, item := range list.Items {
 		resources = append(resources, GenericResource{
-			Name:      item.GetName(),
-			Namespace: item.GetNamespace(),
-			Kind:      item.GetKind(),
-			Workspace: path,
-			Raw:       item.Object,
+			Name:       item.GetName(),
+			Namespace:  item.GetNamespace(),
+			Kind:       item.GetKind(),
+			Workspace:  path,
+			Finalizers: item.GetFinalizers(),
+			Deleting:   item.GetDeletionTimestamp() != nil,
+			Raw:        item.Object,
 		})
 	}
 
@@ -0,0 +1,54 @@
+package kcp
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestMatchResource(t *testing.T) {
+	namespaces := schema.GroupVersionResource{Version: "v1", Resource: "namespaces"}
+	widgets := schema.GroupVersionResource{Group: "example.kcp.io", Version: "v1alpha1", Resource: "widgets"}
+	widgetsV1 := schema.GroupVersionResource{Group: "example.kcp.io", Version: "v1", Resource: "widgets"}
+	gadgets := schema.GroupVersionResource{Group: "other.io", Version: "v1", Resource: "gadgets"}
+	available := []AvailableResource{
+		{GVR: namespaces, Kind: "Namespace", SingularName: "namespace", ShortNames: []string{"ns"}},
+		{GVR: widgets, Kind: "Widget", SingularName: "widget", ShortNames: []string{"wd"}},
+		{GVR: widgetsV1, Kind: "Widget", SingularName: "widget"},
+		{GVR: gadgets, Kind: "Gadget"},
+	}
+
+	tests := []struct {
+		arg     string
+		want    schema.GroupVersionResource
+		wantErr bool
+	}{
+		{arg: "namespaces", want: namespaces},
+		{arg: "namespace", want: namespaces},
+		{arg: "ns", want: namespaces},
+		{arg: "NS", want: namespaces},
+		{arg: "Namespace", want: namespaces},
+		{arg: "widgets", want: widgets},
+		{arg: "wd", want: widgets},
+		{arg: "widgets.example.kcp.io", want: widgets},
+		{arg: "widgets.v1.example.kcp.io", want: widgetsV1},
+		{arg: "gadget", want: gadgets},
+		// Fully qualified resources are used as is for wildcard queries.
+		{arg: "things.v1.remote.io", want: schema.GroupVersionResource{Group: "remote.io", Version: "v1", Resource: "things"}},
+		{arg: "things", wantErr: true},
+		{arg: "things.remote.io", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := MatchResource(available, "root:shop", tt.arg)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("MatchResource(%q) = %v, want an error", tt.arg, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("MatchResource(%q) = %v, %v, want %v", tt.arg, got, err, tt.want)
+		}
+	}
+}
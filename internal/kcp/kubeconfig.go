@@ -0,0 +1,131 @@
+package kcp
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// KubeconfigSyncMode selects how the current workspace is written back to
+// the kubeconfig.
+type KubeconfigSyncMode string
+
+const (
+	// SyncServer points the server of the context's cluster at the workspace.
+	SyncServer KubeconfigSyncMode = "server"
+	// SyncContext creates or updates a dedicated context for the workspace
+	// and makes it the current context, like kubectl ws does.
+	SyncContext KubeconfigSyncMode = "context"
+)
+
+// CurrentWorkspaceContext is the context and cluster name used by SyncContext.
+const CurrentWorkspaceContext = "workspace.kcp.io/current"
+
+// ParseKubeconfigSyncMode validates a sync mode given on the command line.
+func ParseKubeconfigSyncMode(s string) (KubeconfigSyncMode, error) {
+	switch mode := KubeconfigSyncMode(s); mode {
+	case SyncServer, SyncContext:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown kubeconfig sync mode %q, use server or context", s)
+}
+
+// WriteWorkspaceToKubeconfig makes the kubeconfig point at workspace, so
+// that kubectl targets the same workspace afterwards.
+func (c *ClientManager) WriteWorkspaceToKubeconfig(workspace string, mode KubeconfigSyncMode) error {
+	if !c.IsKCP() {
+		return ErrNotKCP
+	}
+
+	pathOptions := clientcmd.NewDefaultPathOptions()
+	pathOptions.LoadingRules.ExplicitPath = c.kubeconfigPath
+
+	config, err := pathOptions.GetStartingConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	contextName := c.contextName
+	if contextName == "" {
+		contextName = config.CurrentContext
+	}
+	kubeContext, ok := config.Contexts[contextName]
+	if !ok {
+		return fmt.Errorf("context %q not found in kubeconfig", contextName)
+	}
+	cluster, ok := config.Clusters[kubeContext.Cluster]
+	if !ok {
+		return fmt.Errorf("cluster %q of context %q not found in kubeconfig", kubeContext.Cluster, contextName)
+	}
+
+	server := c.baseHost + "/clusters/" + workspace
+	switch mode {
+	case SyncServer:
+		cluster.Server = server
+	case SyncContext:
+		workspaceCluster := cluster.DeepCopy()
+		workspaceCluster.Server = server
+		config.Clusters[CurrentWorkspaceContext] = workspaceCluster
+
+		workspaceContext := kubeContext.DeepCopy()
+		workspaceContext.Cluster = CurrentWorkspaceContext
+		config.Contexts[CurrentWorkspaceContext] = workspaceContext
+		config.CurrentContext = CurrentWorkspaceContext
+	default:
+		return fmt.Errorf("unknown kubeconfig sync mode %q", mode)
+	}
+
+	if err := clientcmd.ModifyConfig(pathOptions, *config, true); err != nil {
+		return fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	return nil
+}
+
+// WriteWorkspaceKubeconfig writes a standalone kubeconfig to path with a
+// single context named contextName that points at workspace. It carries the
+// credentials of the active context, with referenced files inlined so the
+// result can be handed to others.
+func (c *ClientManager) WriteWorkspaceKubeconfig(workspace, path, contextName string) error {
+	pathOptions := clientcmd.NewDefaultPathOptions()
+	pathOptions.LoadingRules.ExplicitPath = c.kubeconfigPath
+
+	config, err := pathOptions.GetStartingConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	activeContext := c.contextName
+	if activeContext == "" {
+		activeContext = config.CurrentContext
+	}
+	kubeContext, ok := config.Contexts[activeContext]
+	if !ok {
+		return fmt.Errorf("context %q not found in kubeconfig", activeContext)
+	}
+	cluster, ok := config.Clusters[kubeContext.Cluster]
+	if !ok {
+		return fmt.Errorf("cluster %q of context %q not found in kubeconfig", kubeContext.Cluster, activeContext)
+	}
+	authInfo, ok := config.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return fmt.Errorf("user %q of context %q not found in kubeconfig", kubeContext.AuthInfo, activeContext)
+	}
+
+	workspaceCluster := cluster.DeepCopy()
+	workspaceCluster.Server = c.workspaceHost(workspace)
+
+	out := api.NewConfig()
+	out.Clusters[contextName] = workspaceCluster
+	out.AuthInfos[contextName] = authInfo.DeepCopy()
+	out.Contexts[contextName] = &api.Context{Cluster: contextName, AuthInfo: contextName}
+	out.CurrentContext = contextName
+
+	if err := api.FlattenConfig(out); err != nil {
+		return fmt.Errorf("failed to inline credentials: %w", err)
+	}
+	if err := clientcmd.WriteToFile(*out, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
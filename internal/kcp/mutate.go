@@ -0,0 +1,286 @@
+package kcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
+)
+
+// FieldManager is the field manager name used for all server-side applies.
+const FieldManager = "kcplens"
+
+// ObjectRef identifies a single object in a workspace.
+type ObjectRef struct {
+	Workspace string
+	GVR       schema.GroupVersionResource
+	Namespace string
+	Name      string
+}
+
+func (r ObjectRef) String() string {
+	name := r.Name
+	if r.Namespace != "" {
+		name = r.Namespace + "/" + name
+	}
+	if r.Workspace == "" {
+		return fmt.Sprintf("%s %s", r.GVR.Resource, name)
+	}
+	return fmt.Sprintf("%s %s in %s", r.GVR.Resource, name, r.Workspace)
+}
+
+// resourceClient returns a client for the object's resource in its workspace.
+// It uses a dedicated client so that the current workspace is left untouched
+// when mutations or polls run in the background.
+func (c *ClientManager) resourceClient(ref ObjectRef) (dynamic.ResourceInterface, error) {
+	cfg := rest.CopyConfig(c.RestConfig)
+	cfg.Host = c.workspaceHost(ref.Workspace)
+
+	client, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client for workspace %s: %w", ref.Workspace, err)
+	}
+
+	if ref.Namespace != "" {
+		return client.Resource(ref.GVR).Namespace(ref.Namespace), nil
+	}
+	return client.Resource(ref.GVR), nil
+}
+
+// checkWritable fails if read-only mode forbids changing ref. Dry runs never
+// persist anything and are always allowed.
+func (c *ClientManager) checkWritable(ref ObjectRef, dryRun bool) error {
+	if c.readOnly && !dryRun {
+		return fmt.Errorf("cannot modify %s: %w", ref, ErrReadOnly)
+	}
+	return nil
+}
+
+// GetResource fetches the current state of a single object.
+func (c *ClientManager) GetResource(ctx context.Context, ref ObjectRef) (*unstructured.Unstructured, error) {
+	rc, err := c.resourceClient(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	obj, err := rc.Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", ref, err)
+	}
+	return obj, nil
+}
+
+// UpdateResource replaces an object. The resourceVersion carried by obj is
+// used for optimistic concurrency, so a stale object yields a conflict error.
+// live is the server state obj was edited from and is only used to summarize
+// the change in the audit log.
+func (c *ClientManager) UpdateResource(ctx context.Context, ref ObjectRef, live, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if err := c.checkWritable(ref, false); err != nil {
+		return nil, err
+	}
+
+	rc, err := c.resourceClient(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	updated, err := rc.Update(ctx, obj, metav1.UpdateOptions{FieldManager: FieldManager})
+	c.record(ref, "update", false, c.changeSummary(live, obj), err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update %s: %w", ref, err)
+	}
+	return updated, nil
+}
+
+// ApplyResource server-side applies obj under the kcplens field manager.
+// live is the current server state, nil if the object does not exist, and
+// is only used to summarize the change in the audit log.
+func (c *ClientManager) ApplyResource(ctx context.Context, ref ObjectRef, live, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	applied, err := c.applyObject(ctx, ref, obj, false, false)
+	c.record(ref, "patch", false, c.changeSummary(live, obj), err)
+	return applied, err
+}
+
+// ForceApplyResource is ApplyResource taking over fields owned by other
+// field managers instead of failing with a conflict.
+func (c *ClientManager) ForceApplyResource(ctx context.Context, ref ObjectRef, live, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	applied, err := c.applyObject(ctx, ref, obj, false, true)
+	c.record(ref, "patch", false, "forced, "+c.changeSummary(live, obj), err)
+	return applied, err
+}
+
+// applyObject server-side applies obj, optionally as a dry run that is
+// validated and defaulted by the server but not persisted. It is not
+// audited, so that previews do not show up in the audit log.
+func (c *ClientManager) applyObject(ctx context.Context, ref ObjectRef, obj *unstructured.Unstructured, dryRun, force bool) (*unstructured.Unstructured, error) {
+	if err := c.checkWritable(ref, dryRun); err != nil {
+		return nil, err
+	}
+
+	rc, err := c.resourceClient(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := metav1.ApplyOptions{FieldManager: FieldManager, Force: force}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	applied, err := rc.Apply(ctx, ref.Name, obj, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply %s: %w", ref, err)
+	}
+	return applied, nil
+}
+
+// CreateResource creates a new object.
+func (c *ClientManager) CreateResource(ctx context.Context, ref ObjectRef, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	if err := c.checkWritable(ref, false); err != nil {
+		return nil, err
+	}
+
+	rc, err := c.resourceClient(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := rc.Create(ctx, obj, metav1.CreateOptions{FieldManager: FieldManager})
+	c.record(ref, "create", false, c.changeSummary(nil, obj), err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", ref, err)
+	}
+	return created, nil
+}
+
+// NewWorkspace describes a workspace to be created.
+type NewWorkspace struct {
+	Name string
+	// Type is optional, the server default is used when nil.
+	Type *WorkspaceTypeRef
+	// LocationSelector is an optional label selector choosing the shard.
+	LocationSelector string
+}
+
+// CreateWorkspace creates a child workspace under parent and returns a
+// reference to it.
+func (c *ClientManager) CreateWorkspace(ctx context.Context, parent string, ws NewWorkspace) (ObjectRef, error) {
+	ref := ObjectRef{Workspace: parent, GVR: WorkspaceGVR, Name: ws.Name}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(WorkspaceGVR.GroupVersion().String())
+	obj.SetKind("Workspace")
+	obj.SetName(ws.Name)
+
+	if ws.Type != nil {
+		typeRef := map[string]interface{}{"name": ws.Type.Name}
+		if ws.Type.Path != "" {
+			typeRef["path"] = ws.Type.Path
+		}
+		if err := unstructured.SetNestedMap(obj.Object, typeRef, "spec", "type"); err != nil {
+			return ref, err
+		}
+	}
+
+	if ws.LocationSelector != "" {
+		selector, err := metav1.ParseToLabelSelector(ws.LocationSelector)
+		if err != nil {
+			return ref, fmt.Errorf("invalid location selector: %w", err)
+		}
+		selectorMap, err := runtime.DefaultUnstructuredConverter.ToUnstructured(selector)
+		if err != nil {
+			return ref, err
+		}
+		if err := unstructured.SetNestedMap(obj.Object, selectorMap, "spec", "location", "selector"); err != nil {
+			return ref, err
+		}
+	}
+
+	if _, err := c.CreateResource(ctx, ref, obj); err != nil {
+		return ref, err
+	}
+	c.InvalidateCache(parent)
+	return ref, nil
+}
+
+// WorkspacePhase returns the current phase of a workspace.
+func (c *ClientManager) WorkspacePhase(ctx context.Context, ref ObjectRef) (string, error) {
+	obj, err := c.GetResource(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	return getStatus(*obj), nil
+}
+
+// DeleteOptions controls how DeleteResource removes an object.
+type DeleteOptions struct {
+	Propagation metav1.DeletionPropagation
+	DryRun      bool
+}
+
+// DeleteResource deletes an object. Deleting a workspace drops the cached
+// listing of its parent.
+func (c *ClientManager) DeleteResource(ctx context.Context, ref ObjectRef, opts DeleteOptions) error {
+	if err := c.checkWritable(ref, opts.DryRun); err != nil {
+		return err
+	}
+
+	rc, err := c.resourceClient(ref)
+	if err != nil {
+		return err
+	}
+
+	deleteOpts := metav1.DeleteOptions{}
+	if opts.Propagation != "" {
+		deleteOpts.PropagationPolicy = &opts.Propagation
+	}
+	if opts.DryRun {
+		deleteOpts.DryRun = []string{metav1.DryRunAll}
+	}
+
+	var summary string
+	if opts.Propagation != "" {
+		summary = "propagation: " + string(opts.Propagation)
+	}
+	err = rc.Delete(ctx, ref.Name, deleteOpts)
+	c.record(ref, "delete", opts.DryRun, summary, err)
+	if err != nil {
+		return fmt.Errorf("failed to delete %s: %w", ref, err)
+	}
+
+	if ref.GVR == WorkspaceGVR && !opts.DryRun {
+		c.InvalidateCache(ref.Workspace)
+	}
+	return nil
+}
+
+// EditableYAML renders an object for editing, without managedFields and status.
+func EditableYAML(obj map[string]interface{}) ([]byte, error) {
+	u := &unstructured.Unstructured{Object: obj}
+	u = u.DeepCopy()
+	u.SetManagedFields(nil)
+	unstructured.RemoveNestedField(u.Object, "status")
+	return yaml.Marshal(u.Object)
+}
+
+// UnifiedDiff returns a unified diff between two YAML documents.
+func UnifiedDiff(before, after []byte, fromName, toName string) string {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(before)),
+		B:        difflib.SplitLines(string(after)),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	})
+	if err != nil {
+		return fmt.Sprintf("error generating diff: %v", err)
+	}
+	return diff
+}
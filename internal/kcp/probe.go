@@ -0,0 +1,118 @@
+package kcp
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+// ErrNotKCP is returned by workspace operations on plain Kubernetes clusters.
+var ErrNotKCP = errors.New("not a kcp server")
+
+// ClusterKind tells kcp servers and plain Kubernetes clusters apart.
+type ClusterKind string
+
+const (
+	ClusterKCP        ClusterKind = "kcp"
+	ClusterKubernetes ClusterKind = "k8s"
+	// ClusterUnknown is used when the server could not be reached. It is
+	// treated like kcp, so that errors surface on first use as before.
+	ClusterUnknown ClusterKind = "?"
+)
+
+// probeTimeout bounds each request made while probing a server.
+const probeTimeout = 5 * time.Second
+
+// probeCluster checks whether host is a kcp server by looking for the
+// tenancy.kcp.io API group behind the /clusters/ routing. If that fails,
+// it checks whether host answers as a plain Kubernetes API server.
+func probeCluster(config *rest.Config, host string) ClusterKind {
+	baseHost, workspace := splitClusterURL(host)
+
+	cfg := rest.CopyConfig(config)
+	cfg.Timeout = probeTimeout
+	cfg.Host = baseHost + "/clusters/" + workspace
+	if client, err := discovery.NewDiscoveryClientForConfig(cfg); err == nil {
+		if groups, err := client.ServerGroups(); err == nil {
+			for _, g := range groups.Groups {
+				if g.Name == WorkspaceGVR.Group {
+					return ClusterKCP
+				}
+			}
+		}
+	}
+
+	cfg.Host = host
+	if client, err := discovery.NewDiscoveryClientForConfig(cfg); err == nil {
+		if _, err := client.ServerGroups(); err == nil {
+			return ClusterKubernetes
+		}
+	}
+	return ClusterUnknown
+}
+
+// ProbeContext determines the kind of server a kubeconfig context points at.
+func ProbeContext(kubeconfigPath, contextName string) ClusterKind {
+	config, err := BuildConfigFromContext(kubeconfigPath, contextName)
+	if err != nil {
+		return ClusterUnknown
+	}
+	return probeCluster(config, config.Host)
+}
+
+// Probe determines the kind of server the client is connected to. It makes
+// up to two requests and does not change the client, so that it can run in
+// the background; SetKind applies the result.
+func (c *ClientManager) Probe() ClusterKind {
+	return probeCluster(c.probeConfig, c.probeConfig.Host)
+}
+
+// SetKind records the kind of server found by Probe. The clients of a plain
+// Kubernetes cluster are rebuilt for its server URL, which has no
+// workspaces.
+func (c *ClientManager) SetKind(kind ClusterKind) error {
+	c.kind = kind
+	c.probed = true
+	if kind != ClusterKubernetes {
+		return nil
+	}
+
+	c.baseHost = plainHost(c.probeConfig.Host)
+	c.currentWorkspace, c.initialWorkspace = "", ""
+	c.RestConfig.Host = c.baseHost
+	c.discoveryCache = make(map[string]interface{})
+	return c.buildClients()
+}
+
+// Probed reports whether SetKind has been called.
+func (c *ClientManager) Probed() bool {
+	return c.probed
+}
+
+// Kind returns the kind of server the client is connected to.
+func (c *ClientManager) Kind() ClusterKind {
+	return c.kind
+}
+
+// IsKCP reports whether workspaces are available. Unreachable servers are
+// assumed to be kcp.
+func (c *ClientManager) IsKCP() bool {
+	return c.kind != ClusterKubernetes
+}
+
+// workspaceHost returns the server URL for a workspace. Plain Kubernetes
+// clusters have a single URL for everything.
+func (c *ClientManager) workspaceHost(path string) string {
+	if !c.IsKCP() {
+		return c.baseHost
+	}
+	return c.baseHost + "/clusters/" + path
+}
+
+// plainHost strips a trailing slash from a plain Kubernetes server URL.
+func plainHost(host string) string {
+	return strings.TrimSuffix(host, "/")
+}
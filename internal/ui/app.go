@@ -3,13 +3,23 @@ package ui
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/peter/kcplens/internal/audit"
+	"github.com/peter/kcplens/internal/config"
 	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/keys"
 	"github.com/peter/kcplens/internal/ui/views"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+var statusStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("214")).
+	Margin(0, 2)
+
 type AppState int
 
 const (
@@ -19,48 +29,135 @@ const (
 	StateSyncTargets
 	StateAvailableResources
 	StateResourceInstances
+	StateAuditLog
 )
 
 type AppModel struct {
+	tabState
+
+	cfg                 *config.Config
+	contextSelector     *views.ContextSelector
+	diffView            *views.DiffView
+	deleteDialog        *views.DeleteDialog
+	workspaceForm       *views.WorkspaceForm
+	bindWizard          *views.BindWizard
+	confirmPrompt       *views.ConfirmPrompt
+	commandPrompt       *views.CommandPrompt
+	kubeconfigForm      *views.KubeconfigForm
+	edit                *editSession
+	pendingDelete       *deleteTarget
+	creatingWorkspace   bool
+	binding             bool
+	commanding          bool
+	commandContexts     []string
+	applying            *applySession
+	protectedAction     func() tea.Cmd
+	syncKubeconfig      bool
+	exportingKubeconfig bool
+	windowSize          tea.WindowSizeMsg
+	workspaceJump       *views.WorkspaceJump
+	jumping             bool
+	bookmarks           *config.Bookmarks
+	bookmarkList        *views.BookmarkList
+	bookmarkForm        *views.BookmarkForm
+	browsingBookmarks   bool
+	addingBookmark      bool
+	sessions            *config.Sessions
+	restoreSession      bool
+	historyList         *views.HistoryList
+	browsingHistory     bool
+	header              *views.Header
+	users               map[string]string
+	clusterKinds        map[string]kcp.ClusterKind
+	detailPane          *views.DetailPane
+	detailKey           detailKey
+	split               bool
+	splitRatio          float64
+	tabs                []*tabState
+	activeTab           int
+	nextTabID           int
+	termSize            tea.WindowSizeMsg
+	keys                *keys.KeyMap
+	keyHelp             *views.KeyHelp
+	showingHelp         bool
+}
+
+// tabState is everything that differs between tabs. The active tab's state
+// is embedded in AppModel, the others wait in AppModel.tabs.
+type tabState struct {
+	tabID                 int
 	clientMgr             *kcp.ClientManager
 	workspaceList         *views.WorkspaceList
 	apiList               *views.APIList
 	syncTargetList        *views.SyncTargetList
 	availableResourceList *views.AvailableResourceList
 	resourceInstanceList  *views.ResourceInstanceList
-	contextSelector       *views.ContextSelector
+	auditList             *views.AuditList
 	state                 AppState
 	err                   error
 	loading               bool
 	history               []string
+	status                string
+	startWorkspace        string
+	startView             string
+	noAccess              map[string]bool
+	previousState         AppState
+	commandResources      []kcp.AvailableResource
+	commandResourcesFor   string
+	restoredHistory       []string
+	nav                   navStacks
+	lastLocation          location
+	hasLocation           bool
+	navigating            bool
+	pendingCursor         int
+	pendingNav            *navStacks
+	refreshedAt           time.Time
+	sessionStarted        bool
+	// contextStates remembers the navigation of the contexts this tab has
+	// left, restored when the tab switches back to them.
+	contextStates    map[string]navState
+	workspaceIndexes map[string]*workspaceIndex
+	// restored records the contexts whose last session has been considered
+	// for restoring in this tab.
+	restored map[string]bool
 }
 
-func NewAppModel(cm *kcp.ClientManager) *AppModel {
-	return &AppModel{
-		clientMgr:             cm,
-		loading:               true,
-		workspaceList:         views.NewWorkspaceList(),
-		apiList:               views.NewAPIList(),
-		syncTargetList:        views.NewSyncTargetList(),
-		availableResourceList: views.NewAvailableResourceList(),
-		resourceInstanceList:  views.NewResourceInstanceList(),
-		state:                 StateWorkspaces,
-		history:               []string{},
-	}
+func NewAppModel(cm *kcp.ClientManager, cfg *config.Config, km *keys.KeyMap) *AppModel {
+	m := newAppModel(cm, cfg, km, StateWorkspaces)
+	m.loading = true
+	m.sessionStarted = true
+	return m
 }
 
-func NewAppModelWithContextSelector(cm *kcp.ClientManager, kubeconfigPath string, contexts []string, currentCtx string) *AppModel {
+func NewAppModelWithContextSelector(cm *kcp.ClientManager, cfg *config.Config, km *keys.KeyMap, kubeconfigPath string, contexts []kcp.ContextInfo, currentCtx string) *AppModel {
+	m := newAppModel(cm, cfg, km, StateContextSelect)
+	m.contextSelector = views.NewContextSelector(km, kubeconfigPath, contexts, currentCtx)
+	return m
+}
+
+func newAppModel(cm *kcp.ClientManager, cfg *config.Config, km *keys.KeyMap, state AppState) *AppModel {
 	return &AppModel{
-		clientMgr:             cm,
-		loading:               false,
-		workspaceList:         views.NewWorkspaceList(),
-		apiList:               views.NewAPIList(),
-		syncTargetList:        views.NewSyncTargetList(),
-		availableResourceList: views.NewAvailableResourceList(),
-		resourceInstanceList:  views.NewResourceInstanceList(),
-		contextSelector:       views.NewContextSelector(kubeconfigPath, contexts, currentCtx),
-		state:                 StateContextSelect,
-		history:               []string{},
+		tabState:       newTabState(cm, km, state),
+		cfg:            cfg,
+		keys:           km,
+		diffView:       views.NewDiffView(),
+		deleteDialog:   views.NewDeleteDialog(km),
+		workspaceForm:  views.NewWorkspaceForm(km),
+		bindWizard:     views.NewBindWizard(km),
+		confirmPrompt:  views.NewConfirmPrompt(),
+		commandPrompt:  views.NewCommandPrompt(),
+		kubeconfigForm: views.NewKubeconfigForm(km),
+		workspaceJump:  views.NewWorkspaceJump(),
+		bookmarkList:   views.NewBookmarkList(km),
+		bookmarkForm:   views.NewBookmarkForm(),
+		historyList:    views.NewHistoryList(km),
+		header:         views.NewHeader(),
+		detailPane:     views.NewDetailPane(km),
+		keyHelp:        views.NewKeyHelp(km),
+		users:          make(map[string]string),
+		clusterKinds:   make(map[string]kcp.ClusterKind),
+		tabs:           []*tabState{{}},
+		nextTabID:      1,
 	}
 }
 
@@ -88,6 +185,11 @@ type resourceInstancesLoadedMsg struct {
 	resources []kcp.GenericResource
 }
 
+type auditLoadedMsg struct {
+	entries []audit.Entry
+	path    string
+}
+
 func fetchWorkspacesCmd(cm *kcp.ClientManager, path string) tea.Cmd {
 	return func() tea.Msg {
 		ws, err := cm.DiscoverWorkspaces(context.Background(), path)
@@ -128,9 +230,9 @@ func fetchAvailableResourcesCmd(cm *kcp.ClientManager, path string) tea.Cmd {
 	}
 }
 
-func fetchResourceInstancesCmd(cm *kcp.ClientManager, path string, gvr schema.GroupVersionResource) tea.Cmd {
+func fetchResourceInstancesCmd(cm *kcp.ClientManager, path string, gvr schema.GroupVersionResource, namespace, selector string) tea.Cmd {
 	return func() tea.Msg {
-		res, err := cm.DiscoverResourcesInWorkspace(context.Background(), path, gvr, "")
+		res, err := cm.DiscoverResourcesInWorkspace(context.Background(), path, gvr, namespace, selector)
 		if err != nil {
 			return errorMsg{err}
 		}
@@ -138,49 +240,126 @@ func fetchResourceInstancesCmd(cm *kcp.ClientManager, path string, gvr schema.Gr
 	}
 }
 
+func fetchAuditCmd(log *audit.Log) tea.Cmd {
+	return func() tea.Msg {
+		entries, err := log.Entries()
+		if err != nil {
+			return errorMsg{err}
+		}
+		return auditLoadedMsg{entries: entries, path: log.Path()}
+	}
+}
+
 func (m *AppModel) Init() tea.Cmd {
 	if m.state == StateContextSelect {
-		return m.contextSelector.Init()
+		return tagCmd(tea.Batch(m.contextSelector.Init(), probeContextsCmd(m.contextSelector.KubeconfigPath(), m.contextSelector.Contexts())), m.tabID)
 	}
-	return tea.Batch(
-		fetchWorkspacesCmd(m.clientMgr, "root"),
+	return tagCmd(tea.Batch(
+		m.startCmd(),
 		m.workspaceList.Init(),
-	)
+	), m.tabID)
 }
 
 func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tabMsg); ok {
+		cmd := m.updateTab(msg)
+		m.reportAuditFailure()
+		return m, cmd
+	}
+
+	// Only clicks are handled, so motion is dropped before any work is done.
+	if mouse, ok := msg.(tea.MouseMsg); ok && mouse.Action == tea.MouseActionMotion {
+		return m, nil
+	}
+
+	// Views are sized to the space below the header.
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.termSize = size
+		m.header.SetWidth(size.Width)
+		size.Height -= m.headerHeight()
+		msg = size
+	}
+
+	model, cmd := m.update(msg)
+	if _, ok := msg.(tea.WindowSizeMsg); ok && m.split {
+		m.layoutPanes()
+	}
+	m.markRefreshed(msg)
+	m.reportAuditFailure()
+	m.trackLocation()
+	m.refreshDetail()
+	return model, tagCmd(cmd, m.tabID)
+}
+
+func (m *AppModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		if msg.String() == "ctrl+c" || msg.String() == "q" {
+		if key.Matches(msg, m.keys.ForceQuit) || (key.Matches(msg, m.keys.Quit) && !m.textInputActive() && !m.listFiltering()) {
 			return m, tea.Quit
 		}
 
 		if m.state == StateContextSelect {
-			updated, cmd := m.contextSelector.Update(msg)
-			m.contextSelector = updated.(*views.ContextSelector)
-			if m.contextSelector.SelectedContext() != "" {
-				selectedCtx := m.contextSelector.SelectedContext()
-				cm, err := kcp.NewClientManagerWithContext(m.contextSelector.KubeconfigPath(), selectedCtx)
-				if err != nil {
-					m.err = err
-					m.loading = false
-					return m, tea.Batch(cmd, func() tea.Msg { return errorMsg{err} })
-				}
-				m.clientMgr = cm
-				m.state = StateWorkspaces
-				m.loading = true
-				return m, tea.Batch(cmd, fetchWorkspacesCmd(m.clientMgr, "root"))
-			}
-			return m, cmd
+			return m, m.handleContextSelectKey(msg)
 		}
 
+		if m.protectedAction != nil && !m.loading {
+			return m, m.handleConfirmKey(msg)
+		}
+		if m.commanding {
+			return m, m.handleCommandKey(msg)
+		}
+		if m.edit != nil && !m.loading {
+			return m, m.handleEditKey(msg)
+		}
+		if m.applying != nil && !m.loading {
+			return m, m.handleApplyKey(msg)
+		}
+		if m.pendingDelete != nil && !m.loading {
+			return m, m.handleDeleteKey(msg)
+		}
+		if m.creatingWorkspace && !m.loading {
+			return m, m.handleWorkspaceFormKey(msg)
+		}
+		if m.binding && !m.loading {
+			return m, m.handleBindKey(msg)
+		}
+		if m.exportingKubeconfig {
+			return m, m.handleKubeconfigFormKey(msg)
+		}
+		if m.jumping {
+			return m, m.handleJumpKey(msg)
+		}
+		if m.addingBookmark {
+			return m, m.handleBookmarkFormKey(msg)
+		}
+		if m.browsingBookmarks {
+			return m, m.handleBookmarkListKey(msg)
+		}
+		if m.browsingHistory {
+			return m, m.handleHistoryListKey(msg)
+		}
+		if m.showingHelp {
+			return m, m.handleKeyHelpKey(msg)
+		}
+
+		// While a list takes filter input, all keys belong to the filter.
+		if m.listFiltering() {
+			break
+		}
+		if key.Matches(msg, m.keys.Help) {
+			return m, m.openKeyHelp()
+		}
+		if cmd, ok := m.handleTabKey(msg); ok {
+			return m, cmd
+		}
 		if !m.loading && m.err == nil {
 			cmds = append(cmds, m.handleKey(msg))
 		}
 
 	case tea.WindowSizeMsg:
+		m.windowSize = msg
 		m.workspaceList.Update(msg)
 		m.apiList.Update(msg)
 		m.syncTargetList.Update(msg)
@@ -193,12 +372,29 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.contextSelector != nil {
 			m.contextSelector.Update(msg)
 		}
+		m.bookmarkList.Update(msg)
+		m.historyList.Update(msg)
+		m.keyHelp.Update(msg)
+		m.diffView.Update(msg)
+		m.bindWizard.Update(msg)
+		m.auditList.Update(msg)
 
 	case workspacesLoadedMsg:
 		m.loading = false
 		m.err = nil
 		m.workspaceList.SetCurrentPath(m.clientMgr.CurrentWorkspace())
 		cmds = append(cmds, m.workspaceList.SetItems(msg.workspaces))
+		idx := m.workspaceIndex()
+		idx.add(m.clientMgr.CurrentWorkspace())
+		idx.add(m.workspaceList.Paths()...)
+		if m.startView != "" {
+			cmds = append(cmds, m.openStartView())
+		}
+
+	case startResourceMsg:
+		cmds = append(cmds, m.availableResourceList.SetItems(msg.available))
+		m.availableResourceList.SetTitle("Available Resources in " + m.location())
+		cmds = append(cmds, m.openResourceInstances(msg.gvr))
 
 	case apisLoadedMsg:
 		m.loading = false
@@ -220,9 +416,94 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.err = nil
 		cmds = append(cmds, m.resourceInstanceList.SetItems(msg.resources))
 
+	case auditLoadedMsg:
+		m.loading = false
+		m.err = nil
+		cmds = append(cmds, m.auditList.SetItems(msg.entries, msg.path))
+
+	case editorReadyMsg:
+		m.loading = false
+		return m, m.openEditorCmd(msg.session)
+
+	case editorClosedMsg:
+		return m, m.handleEditorClosed(msg)
+
+	case editAppliedMsg:
+		m.loading = false
+		if m.edit != nil {
+			m.edit.cleanup()
+			m.edit = nil
+		}
+		m.status = fmt.Sprintf("Updated %s", msg.ref)
+		return m, m.refreshCurrentView()
+
+	case editConflictMsg:
+		m.loading = false
+		m.edit = nil
+		m.status = "Conflict: the object changed on the server, re-opening the editor"
+		return m, m.openEditorCmd(msg.session)
+
+	case editOwnershipConflictMsg:
+		m.loading = false
+		return m, m.handleOwnershipConflict(msg)
+
+	case editFailedMsg:
+		m.loading = false
+		m.status = fmt.Sprintf("Edit failed: %v", msg.err)
+		return m, nil
+
+	case deleteDoneMsg, deleteFailedMsg, deletionPollMsg, deletionPendingMsg, deletionCompleteMsg:
+		return m, m.handleDeletionMsg(msg)
+
+	case workspaceTypesLoadedMsg, workspaceCreatedMsg, workspaceCreateFailedMsg, workspacePhasePollMsg, workspacePhaseMsg:
+		return m, m.handleCreateWorkspaceMsg(msg)
+
+	case exportCatalogLoadedMsg, bindingCreatedMsg, bindingFailedMsg, bindingPollMsg, bindingStatusMsg:
+		return m, m.handleBindMsg(msg)
+
+	case kubeconfigWrittenMsg, kubeconfigFailedMsg:
+		return m, m.handleKubeconfigMsg(msg)
+
+	case ancestorAccessMsg, workspaceDeniedMsg:
+		return m, m.handleAccessMsg(msg)
+
+	case applyPlannedMsg, applyPlanFailedMsg, manifestsAppliedMsg:
+		return m, m.handleApplyMsg(msg)
+
+	case contextKindsMsg:
+		for name, kind := range msg.kinds {
+			m.rememberKind(name, kind)
+		}
+		if m.contextSelector != nil {
+			m.contextSelector.SetKinds(msg.kinds)
+		}
+		return m, nil
+
+	case clusterProbedMsg:
+		return m, m.handleClusterProbedMsg(msg)
+
+	case tea.MouseMsg:
+		return m, m.handleMouse(msg)
+
+	case userResolvedMsg:
+		m.users[msg.context] = msg.user
+		return m, nil
+
+	case sessionCheckedMsg:
+		return m, m.handleSessionCheckedMsg(msg)
+
+	case workspaceIndexedMsg:
+		return m, m.handleWorkspaceIndexedMsg(msg)
+
+	case commandResourcesMsg, commandContextsMsg, commandFailedMsg:
+		return m, m.handleCommandMsg(msg)
+
 	case errorMsg:
 		m.err = msg.err
 		m.loading = false
+		// A move by back or forward that failed is not made.
+		m.navigating = false
+		m.pendingNav = nil
 	}
 
 	if !m.loading && m.err == nil {
@@ -233,21 +514,105 @@ func (m *AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *AppModel) handleKey(msg tea.KeyMsg) tea.Cmd {
-	switch msg.String() {
-	case "enter":
+	m.status = ""
+
+	k := m.keys
+	switch {
+	case key.Matches(msg, k.Open):
 		return m.handleEnter()
-	case "a":
+	case key.Matches(msg, k.Edit):
+		return m.startEdit()
+	case key.Matches(msg, k.Delete):
+		return m.startDelete()
+	case key.Matches(msg, k.NewWorkspace):
+		return m.startCreateWorkspace()
+	case key.Matches(msg, k.Bind):
+		return m.startBind()
+	case key.Matches(msg, k.APIs):
 		return m.handleAPIKey()
-	case "s":
+	case key.Matches(msg, k.SyncTargets):
 		return m.handleSyncTargetsKey()
-	case "r":
+	case key.Matches(msg, k.Resources):
 		return m.handleResourcesKey()
-	case "backspace", "esc":
+	case key.Matches(msg, k.AuditLog):
+		return m.handleAuditKey()
+	case key.Matches(msg, k.Command):
+		return m.startCommand()
+	case key.Matches(msg, k.SyncKubeconfig):
+		return m.toggleSyncKubeconfig()
+	case key.Matches(msg, k.ExportKubeconfig):
+		return m.startExportKubeconfig()
+	case key.Matches(msg, k.Contexts):
+		return m.openContextSelector()
+	case key.Matches(msg, k.Jump):
+		return m.startJump()
+	case key.Matches(msg, k.AddBookmark):
+		return m.startAddBookmark()
+	case key.Matches(msg, k.Bookmarks):
+		return m.openBookmarksPanel()
+	case key.Matches(msg, k.HistoryBack):
+		return m.navigateHistory(-1)
+	case key.Matches(msg, k.HistoryForward):
+		return m.navigateHistory(1)
+	case key.Matches(msg, k.History):
+		return m.openHistoryList()
+	case key.Matches(msg, k.NewTab):
+		return m.openTab()
+	case key.Matches(msg, k.Split):
+		return m.toggleSplit()
+	case key.Matches(msg, k.DetailMode):
+		return m.cycleDetailMode()
+	case key.Matches(msg, k.SplitNarrower):
+		return m.resizeSplit(-splitRatioStep)
+	case key.Matches(msg, k.SplitWider):
+		return m.resizeSplit(splitRatioStep)
+	case key.Matches(msg, k.DetailUp):
+		return m.scrollDetail(-3)
+	case key.Matches(msg, k.DetailDown):
+		return m.scrollDetail(3)
+	case key.Matches(msg, k.BookmarkSlots):
+		slot, _ := k.Slot(msg.String())
+		return m.openSlot(slot)
+	case key.Matches(msg, k.Back):
 		return m.handleBackspace()
 	}
 	return nil
 }
 
+// overlayActive reports whether a dialog or panel is shown on top of the
+// current view.
+func (m *AppModel) overlayActive() bool {
+	return m.protectedAction != nil || m.commanding || m.edit != nil || m.applying != nil ||
+		m.pendingDelete != nil || m.creatingWorkspace || m.binding || m.exportingKubeconfig ||
+		m.jumping || m.addingBookmark || m.browsingBookmarks || m.browsingHistory || m.showingHelp
+}
+
+// textInputActive reports whether a dialog currently takes text input, in
+// which case single-letter shortcuts must not fire.
+func (m *AppModel) textInputActive() bool {
+	return m.protectedAction != nil || m.commanding || m.jumping || m.addingBookmark || (m.browsingBookmarks && m.bookmarkList.Filtering()) || (m.browsingHistory && m.historyList.Filtering()) || m.creatingWorkspace || m.exportingKubeconfig || (m.binding && m.bindWizard.TextInputActive())
+}
+
+// listFiltering reports whether the list of the current view is taking
+// filter input.
+func (m *AppModel) listFiltering() bool {
+	switch m.state {
+	case StateContextSelect:
+		return m.contextSelector != nil && m.contextSelector.Filtering()
+	case StateAPIs:
+		return m.apiList.Filtering()
+	case StateSyncTargets:
+		return m.syncTargetList.Filtering()
+	case StateAuditLog:
+		return m.auditList.Filtering()
+	case StateAvailableResources:
+		return m.availableResourceList.Filtering()
+	case StateResourceInstances:
+		return m.resourceInstanceList.Filtering()
+	}
+	return false
+}
+
 func (m *AppModel) handleEnter() tea.Cmd {
 	switch m.state {
 	case StateWorkspaces:
@@ -261,15 +626,22 @@ func (m *AppModel) handleEnter() tea.Cmd {
 	case StateAvailableResources:
 		selected := m.availableResourceList.SelectedResource()
 		if selected != nil {
-			m.state = StateResourceInstances
-			m.loading = true
-			m.resourceInstanceList.SetGVR(selected.GVR)
-			return fetchResourceInstancesCmd(m.clientMgr, m.clientMgr.CurrentWorkspace(), selected.GVR)
+			m.resourceInstanceList.SetScope("", "")
+			return m.openResourceInstances(selected.GVR)
 		}
 	}
 	return nil
 }
 
+func (m *AppModel) openResourceInstances(gvr schema.GroupVersionResource) tea.Cmd {
+	m.state = StateResourceInstances
+	m.loading = true
+	m.resourceInstanceList.SetGVR(gvr)
+	m.resourceInstanceList.SetColumns(m.cfg.ColumnsFor(gvr))
+	namespace, selector := m.resourceInstanceList.Scope()
+	return fetchResourceInstancesCmd(m.clientMgr, m.clientMgr.CurrentWorkspace(), gvr, namespace, selector)
+}
+
 func (m *AppModel) handleAPIKey() tea.Cmd {
 	if m.state == StateWorkspaces {
 		m.state = StateAPIs
@@ -293,12 +665,21 @@ func (m *AppModel) handleResourcesKey() tea.Cmd {
 	if m.state == StateWorkspaces {
 		m.state = StateAvailableResources
 		m.loading = true
-		m.availableResourceList.SetTitle("Available Resources in " + m.clientMgr.CurrentWorkspace())
+		m.availableResourceList.SetTitle("Available Resources in " + m.location())
 		return fetchAvailableResourcesCmd(m.clientMgr, m.clientMgr.CurrentWorkspace())
 	}
 	return nil
 }
 
+func (m *AppModel) handleAuditKey() tea.Cmd {
+	if m.state == StateWorkspaces && m.clientMgr.AuditLog() != nil {
+		m.state = StateAuditLog
+		m.loading = true
+		return fetchAuditCmd(m.clientMgr.AuditLog())
+	}
+	return nil
+}
+
 func (m *AppModel) handleBackspace() tea.Cmd {
 	switch m.state {
 	case StateAPIs:
@@ -312,6 +693,11 @@ func (m *AppModel) handleBackspace() tea.Cmd {
 		m.state = StateWorkspaces
 		return nil
 	case StateAvailableResources:
+		if m.clientMgr.IsKCP() {
+			m.state = StateWorkspaces
+		}
+		return nil
+	case StateAuditLog:
 		m.state = StateWorkspaces
 		return nil
 	case StateResourceInstances:
@@ -322,18 +708,33 @@ func (m *AppModel) handleBackspace() tea.Cmd {
 		m.state = StateAvailableResources
 		return nil
 	case StateWorkspaces:
-		if len(m.history) > 0 {
-			m.loading = true
-			prev := m.history[len(m.history)-1]
-			m.history = m.history[:len(m.history)-1]
-			m.clientMgr.SetWorkspace(prev)
-			return fetchWorkspacesCmd(m.clientMgr, prev)
-		}
+		return m.navigateBack()
 	}
 	return nil
 }
 
 func (m *AppModel) updateCurrentView(msg tea.Msg) tea.Cmd {
+	if m.protectedAction != nil {
+		_, cmd := m.confirmPrompt.Update(msg)
+		return cmd
+	}
+	if m.commanding {
+		_, cmd := m.commandPrompt.Update(msg)
+		return cmd
+	}
+	if m.creatingWorkspace {
+		_, cmd := m.workspaceForm.Update(msg)
+		return cmd
+	}
+	if m.binding {
+		_, cmd := m.bindWizard.Update(msg)
+		return cmd
+	}
+	if m.exportingKubeconfig {
+		_, cmd := m.kubeconfigForm.Update(msg)
+		return cmd
+	}
+
 	switch m.state {
 	case StateContextSelect:
 		if m.contextSelector != nil {
@@ -357,33 +758,91 @@ func (m *AppModel) updateCurrentView(msg tea.Msg) tea.Cmd {
 	case StateResourceInstances:
 		_, cmd := m.resourceInstanceList.Update(msg)
 		return cmd
+	case StateAuditLog:
+		_, cmd := m.auditList.Update(msg)
+		return cmd
 	}
 	return nil
 }
 
 func (m *AppModel) View() string {
+	if !m.sessionStarted {
+		return m.bodyView()
+	}
+	return m.headerView() + "\n" + m.bodyView()
+}
+
+// bodyView renders everything below the header.
+func (m *AppModel) bodyView() string {
 	if m.state == StateContextSelect && m.contextSelector != nil {
 		return m.contextSelector.View()
 	}
 
 	if m.err != nil {
-		return fmt.Sprintf("Error in %s: %v\n\nPress backspace to go back or q to quit.", m.clientMgr.CurrentWorkspace(), m.err)
+		return fmt.Sprintf("\nError in %s: %v\n\nPress backspace to go back or q to quit.", m.location(), m.err)
 	}
 	if m.loading {
-		return fmt.Sprintf("Loading for %s...\n", m.clientMgr.CurrentWorkspace())
+		return fmt.Sprintf("\nLoading for %s...\n", m.location())
+	}
+
+	view := m.currentView()
+	if m.commanding {
+		view += "\n" + m.commandPrompt.View()
+	}
+	if m.status != "" {
+		view += "\n" + statusStyle.Render(m.status)
+	}
+	return view
+}
+
+func (m *AppModel) currentView() string {
+	if m.protectedAction != nil {
+		return m.confirmPrompt.View()
+	}
+	if m.edit != nil || m.applying != nil {
+		return m.diffView.View()
+	}
+	if m.pendingDelete != nil {
+		return m.deleteDialog.View()
+	}
+	if m.creatingWorkspace {
+		return m.workspaceForm.View()
+	}
+	if m.binding {
+		return m.bindWizard.View()
+	}
+	if m.exportingKubeconfig {
+		return m.kubeconfigForm.View()
+	}
+	if m.jumping {
+		return m.workspaceJump.View()
+	}
+	if m.addingBookmark {
+		return m.bookmarkForm.View()
+	}
+	if m.browsingBookmarks {
+		return m.bookmarkList.View()
+	}
+	if m.browsingHistory {
+		return m.historyList.View()
+	}
+	if m.showingHelp {
+		return m.keyHelp.View()
 	}
 
 	switch m.state {
 	case StateWorkspaces:
-		return m.workspaceList.View()
+		return m.withDetail(m.workspaceList.View())
 	case StateAPIs:
-		return m.apiList.View()
+		return m.withDetail(m.apiList.View())
 	case StateSyncTargets:
-		return m.syncTargetList.View()
+		return m.withDetail(m.syncTargetList.View())
 	case StateAvailableResources:
-		return m.availableResourceList.View()
+		return m.withDetail(m.availableResourceList.View())
 	case StateResourceInstances:
-		return m.resourceInstanceList.View()
+		return m.withDetail(m.resourceInstanceList.View())
+	case StateAuditLog:
+		return m.withDetail(m.auditList.View())
 	default:
 		return m.workspaceList.View()
 	}
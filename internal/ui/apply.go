@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/keys"
+)
+
+// applySession holds the dry-run result of manifests waiting for confirmation.
+type applySession struct {
+	workspace string
+	path      string
+	plans     []kcp.ApplyPlan
+}
+
+type applyPlannedMsg struct {
+	session *applySession
+}
+
+type applyPlanFailedMsg struct {
+	err error
+}
+
+type manifestsAppliedMsg struct {
+	session *applySession
+	errs    []error
+}
+
+func planApplyCmd(cm *kcp.ClientManager, workspace, path string) tea.Cmd {
+	return func() tea.Msg {
+		manifests, err := kcp.ReadManifests(path)
+		if err != nil {
+			return applyPlanFailedMsg{err}
+		}
+		if len(manifests) == 0 {
+			return applyPlanFailedMsg{fmt.Errorf("no objects found in %s", path)}
+		}
+
+		plans, err := cm.PlanApply(context.Background(), workspace, manifests)
+		if err != nil {
+			return applyPlanFailedMsg{err}
+		}
+		return applyPlannedMsg{&applySession{workspace: workspace, path: path, plans: plans}}
+	}
+}
+
+func applyManifestsCmd(cm *kcp.ClientManager, session *applySession) tea.Cmd {
+	return func() tea.Msg {
+		errs := cm.ApplyManifests(context.Background(), session.plans)
+		return manifestsAppliedMsg{session: session, errs: errs}
+	}
+}
+
+// startApply dry-runs the manifests at path in the current workspace.
+func (m *AppModel) startApply(path string) tea.Cmd {
+	m.loading = true
+	return planApplyCmd(m.clientMgr, m.clientMgr.CurrentWorkspace(), path)
+}
+
+// planDiff renders all plans of a session as one document for the diff view.
+func planDiff(plans []kcp.ApplyPlan) string {
+	var b strings.Builder
+	for _, plan := range plans {
+		switch plan.Action() {
+		case "error":
+			fmt.Fprintf(&b, "@@ %s %s: %v\n\n", plan.Manifest.Object.GetKind(), plan.Manifest.Object.GetName(), plan.Err)
+		case "deferred":
+			fmt.Fprintf(&b, "@@ %s %s: kind not served yet, planned again once the objects before it are applied\n\n", plan.Manifest.Object.GetKind(), plan.Manifest.Object.GetName())
+		case "unchanged":
+			fmt.Fprintf(&b, "@@ %s: unchanged\n\n", plan.Ref)
+		default:
+			fmt.Fprintf(&b, "@@ %s: %s\n%s\n", plan.Ref, plan.Action(), plan.Diff)
+		}
+	}
+	return b.String()
+}
+
+func (m *AppModel) handleApplyKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.keys.Confirm):
+		if m.denyWrite() {
+			return nil
+		}
+		session := m.applying
+		m.applying = nil
+		return m.guardWrite([]string{session.workspace}, fmt.Sprintf("Apply %s.", session.path), func() tea.Cmd {
+			m.loading = true
+			return applyManifestsCmd(m.clientMgr, session)
+		})
+	case key.Matches(msg, m.keys.Cancel):
+		m.applying = nil
+		m.status = "Apply cancelled"
+		return nil
+	}
+
+	_, cmd := m.diffView.Update(msg)
+	return cmd
+}
+
+func (m *AppModel) handleApplyMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case applyPlanFailedMsg:
+		m.loading = false
+		m.status = fmt.Sprintf("Apply failed: %v", msg.err)
+
+	case applyPlannedMsg:
+		m.loading = false
+		m.applying = msg.session
+
+		failed := 0
+		for _, plan := range msg.session.plans {
+			if plan.Action() == "error" {
+				failed++
+			}
+		}
+		title := fmt.Sprintf("Apply %s to %s (server-side dry run, %d objects", msg.session.path, msg.session.workspace, len(msg.session.plans))
+		if failed > 0 {
+			title += fmt.Sprintf(", %d failing", failed)
+		}
+		title += ")"
+		k := m.keys
+		m.diffView.SetContent(title, planDiff(msg.session.plans), keys.ShortHelp(keys.As(k.Confirm, "Apply"), keys.As(k.Cancel, "Cancel"))+"  [↑/↓] Scroll")
+
+	case manifestsAppliedMsg:
+		m.loading = false
+		applied := 0
+		var failures []string
+		for i, err := range msg.errs {
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", msg.session.plans[i].Manifest.Object.GetName(), err))
+				continue
+			}
+			applied++
+		}
+		m.status = fmt.Sprintf("Applied %d of %d objects to %s", applied, len(msg.errs), msg.session.workspace)
+		if len(failures) > 0 {
+			m.status += "; failed: " + strings.Join(failures, "; ")
+		}
+		return m.refreshCurrentView()
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+)
+
+// bindingPollInterval is how often a new APIBinding is checked until it is Ready.
+const bindingPollInterval = 2 * time.Second
+
+// bindingReadyTimeout is how long a new APIBinding is followed before
+// giving up, for instance when the export it references never resolves.
+const bindingReadyTimeout = 2 * time.Minute
+
+type exportCatalogLoadedMsg struct {
+	exports []kcp.APIExportRef
+	err     error
+}
+
+type bindingCreatedMsg struct {
+	ref kcp.ObjectRef
+}
+
+type bindingFailedMsg struct {
+	err error
+}
+
+type bindingPollMsg struct {
+	ref      kcp.ObjectRef
+	deadline time.Time
+}
+
+type bindingStatusMsg struct {
+	ref      kcp.ObjectRef
+	status   string
+	ready    bool
+	err      error
+	deadline time.Time
+}
+
+func fetchExportCatalogCmd(cm *kcp.ClientManager) tea.Cmd {
+	return func() tea.Msg {
+		exports, err := cm.DiscoverAPIExportCatalog(context.Background())
+		return exportCatalogLoadedMsg{exports: exports, err: err}
+	}
+}
+
+func createBindingCmd(cm *kcp.ClientManager, workspace string, binding kcp.NewAPIBinding) tea.Cmd {
+	return func() tea.Msg {
+		ref, err := cm.CreateAPIBinding(context.Background(), workspace, binding)
+		if err != nil {
+			return bindingFailedMsg{err}
+		}
+		return bindingCreatedMsg{ref}
+	}
+}
+
+func scheduleBindingPoll(ref kcp.ObjectRef, deadline time.Time) tea.Cmd {
+	return tea.Tick(bindingPollInterval, func(time.Time) tea.Msg {
+		return bindingPollMsg{ref: ref, deadline: deadline}
+	})
+}
+
+func pollBindingCmd(cm *kcp.ClientManager, ref kcp.ObjectRef, deadline time.Time) tea.Cmd {
+	return func() tea.Msg {
+		status, ready, err := cm.APIBindingStatus(context.Background(), ref)
+		return bindingStatusMsg{ref: ref, status: status, ready: ready, err: err, deadline: deadline}
+	}
+}
+
+// knownWorkspacePaths returns the workspace paths seen so far, used as
+// completion candidates when asking for a workspace.
+func (m *AppModel) knownWorkspacePaths() []string {
+	seen := map[string]bool{m.clientMgr.CurrentWorkspace(): true}
+	for _, p := range m.history {
+		seen[p] = true
+	}
+	for _, p := range m.workspaceList.Paths() {
+		seen[p] = true
+	}
+
+	paths := make([]string, 0, len(seen))
+	for p := range seen {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// startBind opens the bind wizard for the selected export, or loads the
+// export catalog when started from the workspace list.
+func (m *AppModel) startBind() tea.Cmd {
+	if (m.state == StateWorkspaces || m.state == StateAPIs) && m.denyWrite() {
+		return nil
+	}
+
+	switch m.state {
+	case StateWorkspaces:
+		m.loading = true
+		return fetchExportCatalogCmd(m.clientMgr)
+	case StateAPIs:
+		if m.apiList.InDetailView() || m.apiList.Filtering() {
+			return nil
+		}
+		rel := m.apiList.SelectedRelationship()
+		if rel == nil || rel.Type != "Export" {
+			return nil
+		}
+		export := kcp.NewAPIExportRef(m.clientMgr.CurrentWorkspace(), rel.Raw)
+		m.binding = true
+		return m.bindWizard.OpenForExport(export, m.clientMgr.CurrentWorkspace(), m.knownWorkspacePaths())
+	}
+	return nil
+}
+
+func (m *AppModel) handleBindKey(msg tea.KeyMsg) tea.Cmd {
+	_, cmd := m.bindWizard.Update(msg)
+
+	if m.bindWizard.Cancelled() {
+		m.binding = false
+		m.status = "Bind cancelled"
+		return nil
+	}
+	if !m.bindWizard.Submitted() {
+		return cmd
+	}
+
+	m.binding = false
+	target := m.bindWizard.TargetWorkspace()
+	binding := m.bindWizard.Binding()
+	return m.guardWrite([]string{target}, fmt.Sprintf("Bind APIExport %s.", binding.Export.Name), func() tea.Cmd {
+		m.loading = true
+		return createBindingCmd(m.clientMgr, target, binding)
+	})
+}
+
+func (m *AppModel) handleBindMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case exportCatalogLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Could not load the APIExport catalog: %v", msg.err)
+			return nil
+		}
+		m.binding = true
+		return m.bindWizard.OpenCatalog(msg.exports, m.clientMgr.CurrentWorkspace(), m.knownWorkspacePaths())
+
+	case bindingFailedMsg:
+		m.loading = false
+		m.status = fmt.Sprintf("Bind failed: %v", msg.err)
+
+	case bindingCreatedMsg:
+		m.loading = false
+		m.status = fmt.Sprintf("Created %s, waiting for it to become Ready", msg.ref)
+		return tea.Batch(m.reloadBindingView(msg.ref), scheduleBindingPoll(msg.ref, time.Now().Add(bindingReadyTimeout)))
+
+	case bindingPollMsg:
+		return pollBindingCmd(m.clientMgr, msg.ref, msg.deadline)
+
+	case bindingStatusMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Failed to follow %s: %v", msg.ref, msg.err)
+			return nil
+		}
+		if msg.ready {
+			m.status = fmt.Sprintf("%s is Ready", msg.ref)
+			return m.reloadBindingView(msg.ref)
+		}
+		if time.Now().After(msg.deadline) {
+			status := msg.status
+			if status == "" {
+				status = "none"
+			}
+			m.status = fmt.Sprintf("%s is not Ready after %s, last status: %s", msg.ref, bindingReadyTimeout, status)
+			return m.reloadBindingView(msg.ref)
+		}
+		m.status = fmt.Sprintf("%s: %s", msg.ref, msg.status)
+		return tea.Batch(m.reloadBindingView(msg.ref), scheduleBindingPoll(msg.ref, msg.deadline))
+	}
+	return nil
+}
+
+// reloadBindingView refreshes the API list if it shows the binding's workspace.
+func (m *AppModel) reloadBindingView(ref kcp.ObjectRef) tea.Cmd {
+	if m.state == StateAPIs && m.clientMgr.CurrentWorkspace() == ref.Workspace {
+		return m.reloadCmd()
+	}
+	return nil
+}
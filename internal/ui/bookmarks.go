@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/config"
+	"github.com/peter/kcplens/internal/ui/views"
+)
+
+// SetBookmarks enables bookmarks, which are stored in b. Shared files that
+// were skipped are shown as a warning.
+func (m *AppModel) SetBookmarks(b *config.Bookmarks) {
+	m.bookmarks = b
+	if warnings := b.Warnings(); len(warnings) > 0 {
+		m.status = "Warning: " + strings.Join(warnings, "; ")
+	}
+}
+
+// currentBookmark describes the current location as a bookmark without name.
+func (m *AppModel) currentBookmark() config.Bookmark {
+	bm := config.Bookmark{
+		Context:   m.clientMgr.ContextName(),
+		Workspace: m.clientMgr.CurrentWorkspace(),
+	}
+
+	switch m.state {
+	case StateAPIs:
+		bm.View = StartViewAPIs
+	case StateSyncTargets:
+		bm.View = StartViewSyncTargets
+	case StateAvailableResources:
+		bm.View = StartViewResources
+	case StateResourceInstances:
+		bm.Resource = config.ResourceKey(m.resourceInstanceList.GVR())
+		bm.Namespace, bm.Selector = m.resourceInstanceList.Scope()
+	default:
+		bm.View = StartViewWorkspaces
+	}
+	return bm
+}
+
+// suggestBookmarkName names a bookmark after the last workspace segment and
+// the view.
+func suggestBookmarkName(bm config.Bookmark) string {
+	name := bm.Context
+	if bm.Workspace != "" {
+		name = bm.Workspace[strings.LastIndex(bm.Workspace, ":")+1:]
+	}
+	switch {
+	case bm.Resource != "":
+		name += " " + strings.SplitN(bm.Resource, ".", 2)[0]
+	case bm.View != StartViewWorkspaces:
+		name += " " + bm.View
+	}
+	return name
+}
+
+// startAddBookmark asks for the name of a bookmark of the current location.
+func (m *AppModel) startAddBookmark() tea.Cmd {
+	if m.listFiltering() {
+		return nil
+	}
+	if m.bookmarks == nil {
+		m.status = "Bookmarks are not available"
+		return nil
+	}
+
+	bm := m.currentBookmark()
+	bm.Name = suggestBookmarkName(bm)
+	m.addingBookmark = true
+	return m.bookmarkForm.Open(bm)
+}
+
+func (m *AppModel) handleBookmarkFormKey(msg tea.KeyMsg) tea.Cmd {
+	_, cmd := m.bookmarkForm.Update(msg)
+
+	if m.bookmarkForm.Cancelled() {
+		m.addingBookmark = false
+		return nil
+	}
+	if !m.bookmarkForm.Submitted() {
+		return cmd
+	}
+
+	m.addingBookmark = false
+	bm := m.bookmarkForm.Bookmark()
+	if err := m.bookmarks.Add(bm); err != nil {
+		m.status = fmt.Sprintf("Could not save bookmark: %v", err)
+		return nil
+	}
+	if bm.Slot != 0 {
+		m.status = fmt.Sprintf("Bookmarked %s on key %d", bm.Name, bm.Slot)
+	} else {
+		m.status = fmt.Sprintf("Bookmarked %s", bm.Name)
+	}
+	return nil
+}
+
+// openBookmarksPanel lists all bookmarks.
+func (m *AppModel) openBookmarksPanel() tea.Cmd {
+	if m.listFiltering() {
+		return nil
+	}
+	if m.bookmarks == nil {
+		m.status = "Bookmarks are not available"
+		return nil
+	}
+
+	m.browsingBookmarks = true
+	m.bookmarkList.Update(m.windowSize)
+	return m.bookmarkList.Open(m.bookmarks.All())
+}
+
+func (m *AppModel) handleBookmarkListKey(msg tea.KeyMsg) tea.Cmd {
+	updated, cmd := m.bookmarkList.Update(msg)
+	m.bookmarkList = updated.(*views.BookmarkList)
+
+	if m.bookmarkList.Cancelled() {
+		m.browsingBookmarks = false
+		return nil
+	}
+	if removed := m.bookmarkList.Removed(); removed != nil {
+		if err := m.bookmarks.Remove(removed.Name); err != nil {
+			m.status = fmt.Sprintf("Could not delete bookmark: %v", err)
+			return cmd
+		}
+		m.status = fmt.Sprintf("Deleted bookmark %s", removed.Name)
+		return tea.Batch(cmd, m.bookmarkList.SetItems(m.bookmarks.All()))
+	}
+	if selected := m.bookmarkList.Selected(); selected != nil {
+		m.browsingBookmarks = false
+		return m.openBookmark(*selected)
+	}
+	return cmd
+}
+
+// openSlot opens the bookmark assigned to number key slot.
+func (m *AppModel) openSlot(slot int) tea.Cmd {
+	if m.listFiltering() || m.bookmarks == nil {
+		return nil
+	}
+	bm, ok := m.bookmarks.InSlot(slot)
+	if !ok {
+		m.status = fmt.Sprintf("No bookmark on key %d", slot)
+		return nil
+	}
+	return m.openBookmark(bm)
+}
+
+// openBookmark restores the location of bm in one step, switching the
+// context first if needed.
+func (m *AppModel) openBookmark(bm config.Bookmark) tea.Cmd {
+	if bm.Context != "" && bm.Context != m.clientMgr.ContextName() {
+		if err := m.useContext(bm.Context); err != nil {
+			m.status = fmt.Sprintf("Could not switch to context %s: %v", bm.Context, err)
+			return nil
+		}
+	}
+
+	m.state = StateWorkspaces
+	m.startWorkspace = bm.Workspace
+	m.startView = bm.View
+	if bm.Resource != "" {
+		m.startView = bm.Resource
+	}
+	m.resourceInstanceList.SetScope(bm.Namespace, bm.Selector)
+	m.status = fmt.Sprintf("Opened bookmark %s", bm.Name)
+	return m.startCmd()
+}
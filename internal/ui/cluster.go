@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+)
+
+// contextKindsMsg reports which contexts point at kcp servers.
+type contextKindsMsg struct {
+	kinds map[string]kcp.ClusterKind
+}
+
+// clusterProbedMsg reports the kind of server behind cm.
+type clusterProbedMsg struct {
+	cm   *kcp.ClientManager
+	kind kcp.ClusterKind
+}
+
+// probeClusterCmd finds out whether cm is connected to kcp, which can take
+// seconds for servers that do not answer.
+func probeClusterCmd(cm *kcp.ClientManager) tea.Cmd {
+	return func() tea.Msg {
+		return clusterProbedMsg{cm: cm, kind: cm.Probe()}
+	}
+}
+
+// handleClusterProbedMsg applies the kind of server and starts the client,
+// unless another client has replaced it in the meantime.
+func (m *AppModel) handleClusterProbedMsg(msg clusterProbedMsg) tea.Cmd {
+	if msg.cm != m.clientMgr {
+		return nil
+	}
+	if err := m.clientMgr.SetKind(msg.kind); err != nil {
+		return func() tea.Msg { return errorMsg{err} }
+	}
+	m.rememberKind(m.clientMgr.ContextName(), msg.kind)
+	return m.startCmd()
+}
+
+// rememberKind records the kind of server a context points at, so that
+// clients built for it later need no probe. Servers that could not be
+// reached are probed again next time.
+func (m *AppModel) rememberKind(context string, kind kcp.ClusterKind) {
+	if kind != kcp.ClusterUnknown {
+		m.clusterKinds[context] = kind
+	}
+}
+
+// probeContextsCmd probes all contexts in parallel, so that the context
+// selector can tell kcp servers from plain Kubernetes clusters.
+func probeContextsCmd(kubeconfigPath string, contexts []kcp.ContextInfo) tea.Cmd {
+	if len(contexts) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		var (
+			mu    sync.Mutex
+			wg    sync.WaitGroup
+			kinds = make(map[string]kcp.ClusterKind, len(contexts))
+		)
+		for _, ctx := range contexts {
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				kind := kcp.ProbeContext(kubeconfigPath, name)
+				mu.Lock()
+				kinds[name] = kind
+				mu.Unlock()
+			}(ctx.Name)
+		}
+		wg.Wait()
+		return contextKindsMsg{kinds}
+	}
+}
+
+// location names where the user currently is: the workspace on kcp, or the
+// context on plain Kubernetes clusters, which have no workspaces.
+func (t *tabState) location() string {
+	if !t.clientMgr.IsKCP() {
+		return t.clientMgr.ContextName()
+	}
+	return t.clientMgr.CurrentWorkspace()
+}
+
+// startPlainCmd opens the resource browser of a plain Kubernetes cluster,
+// which takes the place of the workspace list.
+func (m *AppModel) startPlainCmd() tea.Cmd {
+	m.startWorkspace = ""
+	m.history = nil
+	m.noAccess = make(map[string]bool)
+	m.state = StateAvailableResources
+
+	view := m.startView
+	m.startView = ""
+	m.loading = true
+	switch strings.ToLower(view) {
+	case "", StartViewWorkspaces, StartViewAPIs, StartViewResources, StartViewSyncTargets:
+		m.availableResourceList.SetTitle("Available Resources in " + m.location())
+		return fetchAvailableResourcesCmd(m.clientMgr, "")
+	}
+	return resolveStartResourceCmd(m.clientMgr, "", view)
+}
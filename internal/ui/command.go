@@ -0,0 +1,286 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+)
+
+// commandResourcesMsg carries the resource types of the location the
+// command prompt was opened in, used for completion and aliases.
+type commandResourcesMsg struct {
+	location  string
+	resources []kcp.AvailableResource
+}
+
+// commandContextsMsg carries the names of the kubeconfig contexts, read
+// again each time the prompt opens so that changes to the kubeconfig show.
+type commandContextsMsg struct {
+	names []string
+}
+
+type commandFailedMsg struct {
+	err error
+}
+
+// commandLocation identifies the context and workspace resource types were
+// discovered in.
+func (m *AppModel) commandLocation() string {
+	return m.clientMgr.ContextName() + "/" + m.clientMgr.CurrentWorkspace()
+}
+
+func fetchCommandResourcesCmd(cm *kcp.ClientManager, location, path string) tea.Cmd {
+	return func() tea.Msg {
+		resources, err := cm.AvailableResourcesIn(context.Background(), path)
+		if err != nil {
+			// Completion is best effort; commands resolve resources themselves.
+			return nil
+		}
+		return commandResourcesMsg{location: location, resources: resources}
+	}
+}
+
+func fetchCommandContextsCmd(kubeconfigPath string) tea.Cmd {
+	return func() tea.Msg {
+		contexts, _, err := kcp.GetContexts(kubeconfigPath)
+		if err != nil {
+			// Completion is best effort; ctx reports unknown contexts itself.
+			return nil
+		}
+		names := make([]string, len(contexts))
+		for i, ctx := range contexts {
+			names[i] = ctx.Name
+		}
+		return commandContextsMsg{names}
+	}
+}
+
+// resolveCommandResourceCmd resolves a resource alias entered at the prompt.
+// Unlike the start view, an unknown name only sets the status line.
+func resolveCommandResourceCmd(cm *kcp.ClientManager, path, arg string) tea.Cmd {
+	return func() tea.Msg {
+		available, err := cm.AvailableResourcesIn(context.Background(), path)
+		if err != nil {
+			return commandFailedMsg{err}
+		}
+		gvr, err := kcp.MatchResource(available, path, arg)
+		if err != nil {
+			return commandFailedMsg{fmt.Errorf("unknown command or resource %q", arg)}
+		}
+		return startResourceMsg{available: available, gvr: gvr}
+	}
+}
+
+// startCommand opens the ':' command prompt.
+func (m *AppModel) startCommand() tea.Cmd {
+	if m.listFiltering() {
+		return nil
+	}
+	m.commanding = true
+	cmd := m.commandPrompt.Open()
+	m.commandPrompt.SetSuggestions(m.commandSuggestions())
+	cmd = tea.Batch(cmd, fetchCommandContextsCmd(m.clientMgr.KubeconfigPath()))
+
+	if m.commandResourcesFor != m.commandLocation() {
+		m.commandResources = nil
+		m.commandResourcesFor = m.commandLocation()
+		return tea.Batch(cmd, fetchCommandResourcesCmd(m.clientMgr, m.commandResourcesFor, m.clientMgr.CurrentWorkspace()))
+	}
+	return cmd
+}
+
+// commandSuggestions returns every command line the prompt can complete:
+// the commands, known workspaces, contexts and resource aliases.
+func (m *AppModel) commandSuggestions() []string {
+	seen := make(map[string]bool)
+	var suggestions []string
+	add := func(s string) {
+		if !seen[s] {
+			seen[s] = true
+			suggestions = append(suggestions, s)
+		}
+	}
+
+	if m.clientMgr.IsKCP() {
+		add("ws ")
+		add("apis")
+		add("st")
+	}
+	add("res")
+	add("ctx ")
+	add("apply -f ")
+
+	if m.clientMgr.IsKCP() {
+		for _, path := range m.workspaceIndex().sorted() {
+			add("ws " + path)
+		}
+	}
+	for _, name := range m.commandContexts {
+		add("ctx " + name)
+	}
+
+	var aliases []string
+	for _, r := range m.commandResources {
+		aliases = append(aliases, r.Aliases()...)
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		add(alias)
+	}
+	return suggestions
+}
+
+func (m *AppModel) handleCommandMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case commandResourcesMsg:
+		if msg.location != m.commandResourcesFor {
+			return nil
+		}
+		m.commandResources = msg.resources
+		if m.commanding {
+			m.commandPrompt.SetSuggestions(m.commandSuggestions())
+		}
+
+	case commandContextsMsg:
+		m.commandContexts = msg.names
+		if m.commanding {
+			m.commandPrompt.SetSuggestions(m.commandSuggestions())
+		}
+
+	case commandFailedMsg:
+		m.loading = false
+		m.status = msg.err.Error()
+	}
+	return nil
+}
+
+func (m *AppModel) handleCommandKey(msg tea.KeyMsg) tea.Cmd {
+	_, cmd := m.commandPrompt.Update(msg)
+
+	if m.commandPrompt.Cancelled() {
+		m.commanding = false
+		return nil
+	}
+	if !m.commandPrompt.Submitted() {
+		return cmd
+	}
+
+	m.commanding = false
+	return m.runCommand(m.commandPrompt.Command())
+}
+
+// runCommand executes a command line entered at the ':' prompt. Anything
+// that is not a command is looked up as a resource type.
+func (m *AppModel) runCommand(line string) tea.Cmd {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	switch fields[0] {
+	case "apply":
+		if len(fields) < 3 || fields[1] != "-f" {
+			m.status = "Usage: apply -f <file or directory>"
+			return nil
+		}
+		return m.startApply(strings.Join(fields[2:], " "))
+	case "ctx", "context":
+		switch len(fields) {
+		case 1:
+			return m.openContextSelector()
+		case 2:
+			m.previousState = m.state
+			return m.switchContext(fields[1])
+		}
+		m.status = "Usage: ctx [context]"
+		return nil
+	case "ws", "workspace":
+		if !m.clientMgr.IsKCP() {
+			m.status = fmt.Sprintf("Context %s is not a kcp server", m.clientMgr.ContextName())
+			return nil
+		}
+		switch len(fields) {
+		case 1:
+			m.state = StateWorkspaces
+			return nil
+		case 2:
+			return m.goToWorkspace(fields[1])
+		}
+		m.status = "Usage: ws [path]"
+		return nil
+	case "apis", "st", "synctargets":
+		if !m.clientMgr.IsKCP() {
+			m.status = fmt.Sprintf("Context %s is not a kcp server", m.clientMgr.ContextName())
+			return nil
+		}
+		m.state = StateWorkspaces
+		if fields[0] == "apis" {
+			return m.handleAPIKey()
+		}
+		return m.handleSyncTargetsKey()
+	case "res", "resources":
+		m.state = StateWorkspaces
+		return m.handleResourcesKey()
+	}
+
+	namespace, selector, ok := parseScope(fields[1:])
+	if !ok {
+		m.status = fmt.Sprintf("Usage: %s [-n namespace] [-l selector]", fields[0])
+		return nil
+	}
+	m.resourceInstanceList.SetScope(namespace, selector)
+	return m.openResourceByName(fields[0])
+}
+
+// parseScope parses the -n and -l options of a resource command.
+func parseScope(args []string) (namespace, selector string, ok bool) {
+	for i := 0; i < len(args); i += 2 {
+		if i+1 >= len(args) {
+			return "", "", false
+		}
+		switch args[i] {
+		case "-n":
+			namespace = args[i+1]
+		case "-l":
+			selector = args[i+1]
+		default:
+			return "", "", false
+		}
+	}
+	return namespace, selector, true
+}
+
+// goToWorkspace jumps to path. Its ancestors become the history, as if the
+// user had navigated there.
+func (m *AppModel) goToWorkspace(path string) tea.Cmd {
+	if err := kcp.ValidateWorkspacePath(path); err != nil {
+		m.status = err.Error()
+		return nil
+	}
+	m.state = StateWorkspaces
+	m.startWorkspace = path
+	return m.startCmd()
+}
+
+// openResourceByName lists the instances of the resource type named by arg,
+// which may be any name MatchResource accepts.
+func (m *AppModel) openResourceByName(arg string) tea.Cmd {
+	path := m.clientMgr.CurrentWorkspace()
+	if m.commandResourcesFor == m.commandLocation() && m.commandResources != nil {
+		gvr, err := kcp.MatchResource(m.commandResources, path, arg)
+		if err != nil {
+			m.status = fmt.Sprintf("Unknown command or resource %q", arg)
+			return nil
+		}
+		m.availableResourceList.SetItems(m.commandResources)
+		m.availableResourceList.SetTitle("Available Resources in " + m.location())
+		return m.openResourceInstances(gvr)
+	}
+
+	m.loading = true
+	return resolveCommandResourceCmd(m.clientMgr, path, arg)
+}
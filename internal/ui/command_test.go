@@ -0,0 +1,27 @@
+package ui
+
+import "testing"
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		args          []string
+		wantNamespace string
+		wantSelector  string
+		wantOK        bool
+	}{
+		{args: nil, wantOK: true},
+		{args: []string{"-n", "default"}, wantNamespace: "default", wantOK: true},
+		{args: []string{"-l", "tier=frontend"}, wantSelector: "tier=frontend", wantOK: true},
+		{args: []string{"-l", "app=shop", "-n", "prod"}, wantNamespace: "prod", wantSelector: "app=shop", wantOK: true},
+		{args: []string{"-n"}},
+		{args: []string{"-x", "y"}},
+		{args: []string{"default"}},
+	}
+
+	for _, tt := range tests {
+		namespace, selector, ok := parseScope(tt.args)
+		if namespace != tt.wantNamespace || selector != tt.wantSelector || ok != tt.wantOK {
+			t.Errorf("parseScope(%q) = %q, %q, %v, want %q, %q, %v", tt.args, namespace, selector, ok, tt.wantNamespace, tt.wantSelector, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/views"
+)
+
+// navState is the navigation state remembered for a context while another
+// context is active.
+type navState struct {
+	workspace string
+	history   []string
+	noAccess  map[string]bool
+}
+
+// openContextSelector shows the context selector again so that another
+// context can be chosen without restarting.
+func (m *AppModel) openContextSelector() tea.Cmd {
+	if m.listFiltering() {
+		return nil
+	}
+
+	path := m.clientMgr.KubeconfigPath()
+	contexts, _, err := kcp.GetContexts(path)
+	if err != nil {
+		m.status = fmt.Sprintf("Could not load kubeconfig contexts: %v", err)
+		return nil
+	}
+
+	m.contextSelector = views.NewContextSelector(m.keys, path, contexts, m.clientMgr.ContextName())
+	m.contextSelector.AllowCancel()
+	m.contextSelector.Update(m.windowSize)
+	m.previousState = m.state
+	m.state = StateContextSelect
+	return tea.Batch(m.contextSelector.Init(), probeContextsCmd(path, contexts))
+}
+
+func (m *AppModel) handleContextSelectKey(msg tea.KeyMsg) tea.Cmd {
+	updated, cmd := m.contextSelector.Update(msg)
+	m.contextSelector = updated.(*views.ContextSelector)
+
+	if m.contextSelector.Cancelled() {
+		m.state = m.previousState
+		return cmd
+	}
+
+	selected := m.contextSelector.SelectedContext()
+	if selected == "" {
+		return cmd
+	}
+	return tea.Batch(cmd, m.switchContext(selected))
+}
+
+// useContext replaces the client with one for contextName and remembers the
+// navigation state of the previous context. The client is not probed here,
+// which would block; startCmd does that unless the kind of server is known.
+func (m *AppModel) useContext(contextName string) error {
+	cm, err := kcp.NewClientManagerWithContext(m.clientMgr.KubeconfigPath(), contextName)
+	if err != nil {
+		return err
+	}
+	cm.CopySettings(m.clientMgr)
+	if kind, ok := m.clusterKinds[contextName]; ok {
+		if err := cm.SetKind(kind); err != nil {
+			return err
+		}
+	}
+
+	if m.sessionStarted {
+		m.contextStates[m.clientMgr.ContextName()] = navState{
+			workspace: m.clientMgr.CurrentWorkspace(),
+			history:   m.history,
+			noAccess:  m.noAccess,
+		}
+	}
+
+	m.clientMgr = cm
+	m.state = StateWorkspaces
+	m.sessionStarted = true
+	return nil
+}
+
+// switchContext makes contextName the active context. The navigation state
+// of the previous context is kept and restored when switching back to it.
+func (m *AppModel) switchContext(contextName string) tea.Cmd {
+	if m.sessionStarted && contextName == m.clientMgr.ContextName() {
+		m.state = m.previousState
+		return nil
+	}
+
+	if err := m.useContext(contextName); err != nil {
+		if m.sessionStarted {
+			m.state = m.previousState
+			m.status = fmt.Sprintf("Could not switch to context %s: %v", contextName, err)
+			return nil
+		}
+		m.err = err
+		m.loading = false
+		return func() tea.Msg { return errorMsg{err} }
+	}
+	m.status = fmt.Sprintf("Switched to context %s", contextName)
+
+	saved, ok := m.contextStates[contextName]
+	if !ok || !m.clientMgr.Probed() || !m.clientMgr.IsKCP() {
+		return m.startCmd()
+	}
+
+	m.history = saved.history
+	m.noAccess = saved.noAccess
+	m.clientMgr.SetWorkspace(saved.workspace)
+	m.loading = true
+	return tea.Batch(fetchWorkspacesCmd(m.clientMgr, saved.workspace), m.indexWorkspacesCmd())
+}
@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+)
+
+// workspacePollInterval is how often a new workspace is checked until it is Ready.
+const workspacePollInterval = 2 * time.Second
+
+// workspaceReadyTimeout is how long a new workspace is followed before
+// giving up, for instance when its WorkspaceType is missing or its
+// initializers never finish.
+const workspaceReadyTimeout = 2 * time.Minute
+
+type workspaceTypesLoadedMsg struct {
+	types []kcp.WorkspaceTypeRef
+	err   error
+}
+
+type workspaceCreatedMsg struct {
+	ref kcp.ObjectRef
+}
+
+type workspaceCreateFailedMsg struct {
+	err error
+}
+
+type workspacePhasePollMsg struct {
+	ref      kcp.ObjectRef
+	deadline time.Time
+}
+
+type workspacePhaseMsg struct {
+	ref      kcp.ObjectRef
+	phase    string
+	err      error
+	deadline time.Time
+}
+
+func fetchWorkspaceTypesCmd(cm *kcp.ClientManager, path string) tea.Cmd {
+	return func() tea.Msg {
+		types, err := cm.DiscoverWorkspaceTypes(context.Background(), path)
+		return workspaceTypesLoadedMsg{types: types, err: err}
+	}
+}
+
+func createWorkspaceCmd(cm *kcp.ClientManager, parent string, ws kcp.NewWorkspace) tea.Cmd {
+	return func() tea.Msg {
+		ref, err := cm.CreateWorkspace(context.Background(), parent, ws)
+		if err != nil {
+			return workspaceCreateFailedMsg{err}
+		}
+		return workspaceCreatedMsg{ref}
+	}
+}
+
+func scheduleWorkspacePhasePoll(ref kcp.ObjectRef, deadline time.Time) tea.Cmd {
+	return tea.Tick(workspacePollInterval, func(time.Time) tea.Msg {
+		return workspacePhasePollMsg{ref: ref, deadline: deadline}
+	})
+}
+
+func pollWorkspacePhaseCmd(cm *kcp.ClientManager, ref kcp.ObjectRef, deadline time.Time) tea.Cmd {
+	return func() tea.Msg {
+		phase, err := cm.WorkspacePhase(context.Background(), ref)
+		return workspacePhaseMsg{ref: ref, phase: phase, err: err, deadline: deadline}
+	}
+}
+
+// startCreateWorkspace loads the workspace types and then opens the form.
+func (m *AppModel) startCreateWorkspace() tea.Cmd {
+	if m.state != StateWorkspaces || m.denyWrite() {
+		return nil
+	}
+	m.loading = true
+	return fetchWorkspaceTypesCmd(m.clientMgr, m.clientMgr.CurrentWorkspace())
+}
+
+func (m *AppModel) handleWorkspaceFormKey(msg tea.KeyMsg) tea.Cmd {
+	_, cmd := m.workspaceForm.Update(msg)
+
+	if m.workspaceForm.Cancelled() {
+		m.creatingWorkspace = false
+		m.status = "Workspace creation cancelled"
+		return nil
+	}
+	if !m.workspaceForm.Submitted() {
+		return cmd
+	}
+
+	m.creatingWorkspace = false
+	parent := m.clientMgr.CurrentWorkspace()
+	ws := m.workspaceForm.Workspace()
+	return m.guardWrite([]string{parent, parent + ":" + ws.Name}, fmt.Sprintf("Create workspace %s.", ws.Name), func() tea.Cmd {
+		m.loading = true
+		return createWorkspaceCmd(m.clientMgr, parent, ws)
+	})
+}
+
+func (m *AppModel) handleCreateWorkspaceMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case workspaceTypesLoadedMsg:
+		m.loading = false
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Could not list workspace types: %v", msg.err)
+		}
+		m.creatingWorkspace = true
+		return m.workspaceForm.Open(m.clientMgr.CurrentWorkspace(), msg.types)
+
+	case workspaceCreateFailedMsg:
+		m.loading = false
+		m.status = fmt.Sprintf("Create failed: %v", msg.err)
+
+	case workspaceCreatedMsg:
+		m.loading = false
+		m.status = fmt.Sprintf("Created workspace %s, waiting for it to become Ready", msg.ref.Name)
+		return tea.Batch(m.reloadCmd(), scheduleWorkspacePhasePoll(msg.ref, time.Now().Add(workspaceReadyTimeout)))
+
+	case workspacePhasePollMsg:
+		return pollWorkspacePhaseCmd(m.clientMgr, msg.ref, msg.deadline)
+
+	case workspacePhaseMsg:
+		if msg.err != nil {
+			m.status = fmt.Sprintf("Failed to follow workspace %s: %v", msg.ref.Name, msg.err)
+			return nil
+		}
+
+		var reload tea.Cmd
+		if m.state == StateWorkspaces && m.clientMgr.CurrentWorkspace() == msg.ref.Workspace {
+			reload = m.reloadCmd()
+		}
+		if msg.phase == "Ready" {
+			m.status = fmt.Sprintf("Workspace %s is Ready", msg.ref.Name)
+			return reload
+		}
+		if time.Now().After(msg.deadline) {
+			phase := msg.phase
+			if phase == "" {
+				phase = "none"
+			}
+			m.status = fmt.Sprintf("Workspace %s is not Ready after %s, last phase: %s", msg.ref.Name, workspaceReadyTimeout, phase)
+			return reload
+		}
+		m.status = fmt.Sprintf("Workspace %s: %s", msg.ref.Name, msg.phase)
+		return tea.Batch(reload, scheduleWorkspacePhasePoll(msg.ref, msg.deadline))
+	}
+	return nil
+}
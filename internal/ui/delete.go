@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// deletionPollInterval is how often a deleted object is checked until it is gone.
+const deletionPollInterval = 2 * time.Second
+
+// deleteTarget is an object awaiting confirmation in the delete dialog,
+// together with the state whose list shows it.
+type deleteTarget struct {
+	ref   kcp.ObjectRef
+	state AppState
+}
+
+type deleteDoneMsg struct {
+	target deleteTarget
+	dryRun bool
+}
+
+type deleteFailedMsg struct {
+	err error
+}
+
+type deletionPollMsg struct {
+	target deleteTarget
+}
+
+type deletionPendingMsg struct {
+	target     deleteTarget
+	finalizers []string
+}
+
+type deletionCompleteMsg struct {
+	target deleteTarget
+}
+
+func deleteCmd(cm *kcp.ClientManager, target deleteTarget, opts kcp.DeleteOptions) tea.Cmd {
+	return func() tea.Msg {
+		if err := cm.DeleteResource(context.Background(), target.ref, opts); err != nil {
+			return deleteFailedMsg{err}
+		}
+		return deleteDoneMsg{target: target, dryRun: opts.DryRun}
+	}
+}
+
+func scheduleDeletionPoll(target deleteTarget) tea.Cmd {
+	return tea.Tick(deletionPollInterval, func(time.Time) tea.Msg {
+		return deletionPollMsg{target}
+	})
+}
+
+func pollDeletionCmd(cm *kcp.ClientManager, target deleteTarget) tea.Cmd {
+	return func() tea.Msg {
+		obj, err := cm.GetResource(context.Background(), target.ref)
+		if apierrors.IsNotFound(err) {
+			return deletionCompleteMsg{target}
+		}
+		if err != nil {
+			return deleteFailedMsg{err}
+		}
+		return deletionPendingMsg{target: target, finalizers: obj.GetFinalizers()}
+	}
+}
+
+// startDelete opens the delete dialog for the item selected in the current view.
+func (m *AppModel) startDelete() tea.Cmd {
+	ref, ok := m.selectedObjectRef()
+	if !ok {
+		return nil
+	}
+
+	name := ref.Name
+	if ref.Namespace != "" {
+		name = ref.Namespace + "/" + name
+	}
+	m.deleteDialog.Open(ref.GVR.Resource, name, ref.Workspace, m.clientMgr.ReadOnly())
+	m.pendingDelete = &deleteTarget{ref: ref, state: m.state}
+	return nil
+}
+
+func (m *AppModel) handleDeleteKey(msg tea.KeyMsg) tea.Cmd {
+	m.deleteDialog.Update(msg)
+
+	if m.deleteDialog.Cancelled() {
+		m.pendingDelete = nil
+		m.status = "Delete cancelled"
+		return nil
+	}
+	if !m.deleteDialog.Confirmed() {
+		return nil
+	}
+
+	target := *m.pendingDelete
+	m.pendingDelete = nil
+	opts := kcp.DeleteOptions{
+		Propagation: m.deleteDialog.Propagation(),
+		DryRun:      m.deleteDialog.DryRun(),
+	}
+	run := func() tea.Cmd {
+		m.loading = true
+		return deleteCmd(m.clientMgr, target, opts)
+	}
+	if opts.DryRun {
+		return run()
+	}
+	return m.guardWrite(protectedPaths(target.ref), fmt.Sprintf("Delete %s.", target.ref), run)
+}
+
+// showsTarget reports whether the current view still lists the target.
+func (m *AppModel) showsTarget(target deleteTarget) bool {
+	if m.state != target.state || m.clientMgr.CurrentWorkspace() != target.ref.Workspace {
+		return false
+	}
+	if m.state == StateResourceInstances {
+		return m.resourceInstanceList.GVR() == target.ref.GVR
+	}
+	return true
+}
+
+func (m *AppModel) handleDeletionMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case deleteDoneMsg:
+		m.loading = false
+		if msg.dryRun {
+			m.status = fmt.Sprintf("Dry run: %s would be deleted", msg.target.ref)
+			return nil
+		}
+		m.status = fmt.Sprintf("Deleting %s", msg.target.ref)
+		return tea.Batch(m.reloadCmd(), scheduleDeletionPoll(msg.target))
+
+	case deleteFailedMsg:
+		m.loading = false
+		m.status = fmt.Sprintf("Delete failed: %v", msg.err)
+
+	case deletionPollMsg:
+		return pollDeletionCmd(m.clientMgr, msg.target)
+
+	case deletionPendingMsg:
+		if !m.showsTarget(msg.target) {
+			return nil
+		}
+		m.status = fmt.Sprintf("Waiting for %s to terminate", msg.target.ref)
+		if len(msg.finalizers) > 0 {
+			m.status += fmt.Sprintf(" (finalizers: %s)", strings.Join(msg.finalizers, ", "))
+		}
+		return tea.Batch(m.reloadCmd(), scheduleDeletionPoll(msg.target))
+
+	case deletionCompleteMsg:
+		m.status = fmt.Sprintf("Deleted %s", msg.target.ref)
+		if m.showsTarget(msg.target) {
+			return m.reloadCmd()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,377 @@
+package ui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/keys"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// editSession tracks one object being edited in an external editor.
+type editSession struct {
+	ref  kcp.ObjectRef
+	file string
+	// original is the server state the current edit round started from.
+	original []byte
+	// live is the object original was rendered from.
+	live *unstructured.Unstructured
+	// edited is the content of the temp file after the editor was closed,
+	// without the leading comment header.
+	edited []byte
+	// ownershipConflict is set when a server-side apply of edited failed
+	// because other field managers own some of the changed fields.
+	ownershipConflict bool
+}
+
+// editWrite is how the edited object is written back.
+type editWrite int
+
+const (
+	editUpdate editWrite = iota
+	editApply
+	editForceApply
+)
+
+func (s *editSession) cleanup() {
+	if s.file != "" {
+		os.Remove(s.file)
+	}
+}
+
+type editorReadyMsg struct {
+	session *editSession
+}
+
+type editorClosedMsg struct {
+	session *editSession
+	err     error
+}
+
+type editAppliedMsg struct {
+	ref kcp.ObjectRef
+}
+
+type editConflictMsg struct {
+	session *editSession
+}
+
+type editFailedMsg struct {
+	err error
+}
+
+type editOwnershipConflictMsg struct {
+	session *editSession
+	err     error
+}
+
+func startEditCmd(cm *kcp.ClientManager, ref kcp.ObjectRef) tea.Cmd {
+	return func() tea.Msg {
+		obj, err := cm.GetResource(context.Background(), ref)
+		if err != nil {
+			return editFailedMsg{err}
+		}
+		content, err := kcp.EditableYAML(obj.Object)
+		if err != nil {
+			return editFailedMsg{err}
+		}
+
+		f, err := os.CreateTemp("", "kcplens-edit-*.yaml")
+		if err != nil {
+			return editFailedMsg{fmt.Errorf("failed to create temp file: %w", err)}
+		}
+		defer f.Close()
+
+		header := fmt.Sprintf("# Editing %s\n# Lines starting with '#' at the top are ignored. Save an unchanged file to cancel.\n", ref)
+		if _, err := f.WriteString(header + string(content)); err != nil {
+			os.Remove(f.Name())
+			return editFailedMsg{fmt.Errorf("failed to write temp file: %w", err)}
+		}
+
+		return editorReadyMsg{&editSession{ref: ref, file: f.Name(), original: content, live: obj}}
+	}
+}
+
+// editorCommand builds the command for the user's editor, preferring
+// KUBE_EDITOR and EDITOR the same way kubectl edit does.
+func editorCommand(file string) *exec.Cmd {
+	editor := os.Getenv("KUBE_EDITOR")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	return exec.Command(args[0], append(args[1:], file)...)
+}
+
+// openEditorCmd runs the editor. Bubble Tea delivers the result itself, so
+// it is addressed to the tab explicitly.
+func (m *AppModel) openEditorCmd(session *editSession) tea.Cmd {
+	id := m.tabID
+	return runtimeCmd(tea.ExecProcess(editorCommand(session.file), func(err error) tea.Msg {
+		return tabMsg{id: id, msg: editorClosedMsg{session: session, err: err}}
+	}))
+}
+
+// stripHeader removes the leading comment lines written above the object.
+func stripHeader(content []byte) []byte {
+	for bytes.HasPrefix(content, []byte("#")) {
+		idx := bytes.IndexByte(content, '\n')
+		if idx < 0 {
+			return nil
+		}
+		content = content[idx+1:]
+	}
+	return content
+}
+
+func applyEditCmd(cm *kcp.ClientManager, session *editSession, write editWrite) tea.Cmd {
+	return func() tea.Msg {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal(session.edited, &obj.Object); err != nil {
+			return editFailedMsg{fmt.Errorf("invalid YAML: %w", err)}
+		}
+
+		var err error
+		switch write {
+		case editApply:
+			obj.SetManagedFields(nil)
+			_, err = cm.ApplyResource(context.Background(), session.ref, session.live, obj)
+		case editForceApply:
+			obj.SetManagedFields(nil)
+			_, err = cm.ForceApplyResource(context.Background(), session.ref, session.live, obj)
+		default:
+			_, err = cm.UpdateResource(context.Background(), session.ref, session.live, obj)
+		}
+		if err == nil {
+			return editAppliedMsg{session.ref}
+		}
+		// Conflicts with other field managers are conflicts as well, but a
+		// newer resourceVersion does not resolve them.
+		if apierrors.HasStatusCause(err, metav1.CauseTypeFieldManagerConflict) {
+			return editOwnershipConflictMsg{session: session, err: err}
+		}
+		if !apierrors.IsConflict(err) {
+			return editFailedMsg{err}
+		}
+
+		return rebaseEdit(cm, session, obj, err)
+	}
+}
+
+// rebaseEdit prepares a new edit round after a conflict. The user's changes
+// are kept, the resourceVersion is moved to the latest one on the server and
+// the header shows what changed on the server in the meantime.
+func rebaseEdit(cm *kcp.ClientManager, session *editSession, edited *unstructured.Unstructured, conflict error) tea.Msg {
+	latest, err := cm.GetResource(context.Background(), session.ref)
+	if err != nil {
+		return editFailedMsg{err}
+	}
+	latestYAML, err := kcp.EditableYAML(latest.Object)
+	if err != nil {
+		return editFailedMsg{err}
+	}
+
+	edited.SetResourceVersion(latest.GetResourceVersion())
+	editedYAML, err := yaml.Marshal(edited.Object)
+	if err != nil {
+		return editFailedMsg{err}
+	}
+
+	var header strings.Builder
+	fmt.Fprintf(&header, "# Editing %s\n", session.ref)
+	fmt.Fprintf(&header, "# %v\n", conflict)
+	header.WriteString("# The object was changed on the server while you were editing. Your changes are kept below.\n")
+	header.WriteString("# Changes made on the server:\n")
+	for _, line := range strings.Split(strings.TrimRight(kcp.UnifiedDiff(session.original, latestYAML, "yours", "server"), "\n"), "\n") {
+		header.WriteString("#   " + line + "\n")
+	}
+
+	if err := os.WriteFile(session.file, append([]byte(header.String()), editedYAML...), 0o600); err != nil {
+		return editFailedMsg{fmt.Errorf("failed to write temp file: %w", err)}
+	}
+
+	session.original = latestYAML
+	session.live = latest
+	session.edited = nil
+	return editConflictMsg{session}
+}
+
+// startEdit opens the editor for the item selected in the current view.
+func (m *AppModel) startEdit() tea.Cmd {
+	if m.state != StateAPIs && m.state != StateResourceInstances {
+		return nil
+	}
+	ref, ok := m.selectedObjectRef()
+	if !ok || m.denyWrite() {
+		return nil
+	}
+	m.loading = true
+	return startEditCmd(m.clientMgr, ref)
+}
+
+// selectedObjectRef returns the object highlighted in the current list view.
+func (m *AppModel) selectedObjectRef() (kcp.ObjectRef, bool) {
+	switch m.state {
+	case StateWorkspaces:
+		if node := m.workspaceList.SelectedNode(); node != nil {
+			return kcp.ObjectRef{
+				Workspace: kcp.ParentPath(node.Path),
+				GVR:       kcp.WorkspaceGVR,
+				Name:      node.Name,
+			}, true
+		}
+	case StateAPIs:
+		if m.apiList.InDetailView() || m.apiList.Filtering() {
+			return kcp.ObjectRef{}, false
+		}
+		if rel := m.apiList.SelectedRelationship(); rel != nil {
+			return kcp.ObjectRef{
+				Workspace: m.clientMgr.CurrentWorkspace(),
+				GVR:       rel.GVR,
+				Name:      rel.Name,
+			}, true
+		}
+	case StateResourceInstances:
+		if m.resourceInstanceList.InDetailView() || m.resourceInstanceList.Filtering() {
+			return kcp.ObjectRef{}, false
+		}
+		if res := m.resourceInstanceList.SelectedResource(); res != nil {
+			return kcp.ObjectRef{
+				Workspace: res.Workspace,
+				GVR:       m.resourceInstanceList.GVR(),
+				Namespace: res.Namespace,
+				Name:      res.Name,
+			}, true
+		}
+	}
+	return kcp.ObjectRef{}, false
+}
+
+func (m *AppModel) handleEditorClosed(msg editorClosedMsg) tea.Cmd {
+	session := msg.session
+	if msg.err != nil {
+		session.cleanup()
+		m.edit = nil
+		m.status = fmt.Sprintf("Editor failed: %v", msg.err)
+		return nil
+	}
+
+	content, err := os.ReadFile(session.file)
+	if err != nil {
+		session.cleanup()
+		m.edit = nil
+		m.status = fmt.Sprintf("Failed to read edited file: %v", err)
+		return nil
+	}
+
+	session.edited = stripHeader(content)
+	if bytes.Equal(bytes.TrimSpace(session.edited), bytes.TrimSpace(session.original)) {
+		session.cleanup()
+		m.edit = nil
+		m.status = "Edit cancelled, no changes made"
+		return nil
+	}
+
+	session.ownershipConflict = false
+	m.showEditDiff(session)
+	return nil
+}
+
+// handleOwnershipConflict keeps the changes open after other field managers
+// rejected a server-side apply, offering to force it or to update instead.
+func (m *AppModel) handleOwnershipConflict(msg editOwnershipConflictMsg) tea.Cmd {
+	msg.session.ownershipConflict = true
+	m.status = fmt.Sprintf("Conflict: %v", msg.err)
+	m.showEditDiff(msg.session)
+	return nil
+}
+
+func (m *AppModel) showEditDiff(session *editSession) {
+	m.edit = session
+	title := fmt.Sprintf("Changes to %s", session.ref)
+	k := m.keys
+	help := keys.ShortHelp(k.Update, k.ServerSideApply, k.EditAgain, keys.As(k.Cancel, "Cancel"))
+	if session.ownershipConflict {
+		title += " (fields owned by other field managers)"
+		help = keys.ShortHelp(k.ForceApply, k.Update, k.EditAgain, keys.As(k.Cancel, "Cancel"))
+	}
+	m.diffView.SetContent(title, kcp.UnifiedDiff(session.original, session.edited, "server", "edited"), help)
+}
+
+// editWriteFor returns how the changes are written back for a key of the
+// diff view. Forcing is only offered after an ownership conflict.
+func (m *AppModel) editWriteFor(msg tea.KeyMsg) (editWrite, bool) {
+	switch {
+	case key.Matches(msg, m.keys.Update):
+		return editUpdate, true
+	case key.Matches(msg, m.keys.ServerSideApply):
+		return editApply, true
+	case key.Matches(msg, m.keys.ForceApply) && m.edit.ownershipConflict:
+		return editForceApply, true
+	}
+	return 0, false
+}
+
+func (m *AppModel) handleEditKey(msg tea.KeyMsg) tea.Cmd {
+	if write, ok := m.editWriteFor(msg); ok {
+		session := m.edit
+		return m.guardWrite(protectedPaths(session.ref), fmt.Sprintf("Apply changes to %s.", session.ref), func() tea.Cmd {
+			m.loading = true
+			return applyEditCmd(m.clientMgr, session, write)
+		})
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.EditAgain):
+		session := m.edit
+		m.edit = nil
+		return m.openEditorCmd(session)
+	case key.Matches(msg, m.keys.Cancel):
+		m.edit.cleanup()
+		m.edit = nil
+		m.status = "Edit cancelled"
+		return nil
+	}
+
+	_, cmd := m.diffView.Update(msg)
+	return cmd
+}
+
+// refreshCurrentView reloads the list shown in the current state.
+func (m *AppModel) refreshCurrentView() tea.Cmd {
+	cmd := m.reloadCmd()
+	if cmd != nil {
+		m.loading = true
+	}
+	return cmd
+}
+
+// reloadCmd fetches the list of the current state again without switching
+// to the loading screen.
+func (m *AppModel) reloadCmd() tea.Cmd {
+	path := m.clientMgr.CurrentWorkspace()
+	switch m.state {
+	case StateWorkspaces:
+		m.clientMgr.InvalidateCache(path)
+		return fetchWorkspacesCmd(m.clientMgr, path)
+	case StateAPIs:
+		return fetchAPIsCmd(m.clientMgr, path)
+	case StateResourceInstances:
+		namespace, selector := m.resourceInstanceList.Scope()
+		return fetchResourceInstancesCmd(m.clientMgr, path, m.resourceInstanceList.GVR(), namespace, selector)
+	}
+	return nil
+}
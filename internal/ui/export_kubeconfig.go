@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+)
+
+type kubeconfigWrittenMsg struct {
+	path         string
+	clipboardErr error
+}
+
+type kubeconfigFailedMsg struct {
+	err error
+}
+
+func writeKubeconfigCmd(cm *kcp.ClientManager, workspace, path, contextName string) tea.Cmd {
+	return func() tea.Msg {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return kubeconfigFailedMsg{err}
+		}
+		if err := cm.WriteWorkspaceKubeconfig(workspace, abs, contextName); err != nil {
+			return kubeconfigFailedMsg{err}
+		}
+		return kubeconfigWrittenMsg{path: abs, clipboardErr: clipboard.WriteAll(exportLine(abs))}
+	}
+}
+
+func exportLine(path string) string {
+	return "export KUBECONFIG=" + path
+}
+
+// startExportKubeconfig opens the kubeconfig form for the selected
+// workspace, or the current one if nothing is selected.
+func (m *AppModel) startExportKubeconfig() tea.Cmd {
+	if m.state != StateWorkspaces {
+		return nil
+	}
+
+	workspace := m.clientMgr.CurrentWorkspace()
+	if selected := m.workspaceList.SelectedNode(); selected != nil {
+		workspace = selected.Path
+	}
+
+	path := strings.ReplaceAll(workspace, ":", "-") + ".kubeconfig"
+	if cwd, err := os.Getwd(); err == nil {
+		path = filepath.Join(cwd, path)
+	}
+
+	m.exportingKubeconfig = true
+	return m.kubeconfigForm.Open(workspace, path, workspace)
+}
+
+func (m *AppModel) handleKubeconfigFormKey(msg tea.KeyMsg) tea.Cmd {
+	_, cmd := m.kubeconfigForm.Update(msg)
+
+	if m.kubeconfigForm.Cancelled() {
+		m.exportingKubeconfig = false
+		return nil
+	}
+	if !m.kubeconfigForm.Submitted() {
+		return cmd
+	}
+
+	m.exportingKubeconfig = false
+	return writeKubeconfigCmd(m.clientMgr, m.kubeconfigForm.Workspace(), m.kubeconfigForm.Path(), m.kubeconfigForm.Context())
+}
+
+func (m *AppModel) handleKubeconfigMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case kubeconfigFailedMsg:
+		m.status = fmt.Sprintf("Writing kubeconfig failed: %v", msg.err)
+	case kubeconfigWrittenMsg:
+		if msg.clipboardErr != nil {
+			m.status = fmt.Sprintf("Wrote %s (clipboard unavailable: %v): %s", msg.path, msg.clipboardErr, exportLine(msg.path))
+		} else {
+			m.status = fmt.Sprintf("Wrote %s and copied %q to the clipboard", msg.path, exportLine(msg.path))
+		}
+	}
+	return nil
+}
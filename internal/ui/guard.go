@@ -0,0 +1,58 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+)
+
+const readOnlyStatus = "Read-only mode: changes are disabled"
+
+// denyWrite reports whether write actions are disabled and tells the user.
+// The kcp layer rejects writes on its own, this only avoids opening dialogs
+// whose result could never be applied.
+func (m *AppModel) denyWrite() bool {
+	if m.clientMgr.ReadOnly() {
+		m.status = readOnlyStatus
+		return true
+	}
+	return false
+}
+
+// protectedPaths returns the workspaces affected by changing ref. Changes to
+// a workspace object also affect the workspace itself.
+func protectedPaths(ref kcp.ObjectRef) []string {
+	if ref.GVR == kcp.WorkspaceGVR {
+		return []string{ref.Workspace, ref.Workspace + ":" + ref.Name}
+	}
+	return []string{ref.Workspace}
+}
+
+// guardWrite runs a change right away, unless one of the workspaces is
+// protected. In that case the change only runs after the user typed the
+// workspace path into the confirmation prompt.
+func (m *AppModel) guardWrite(workspaces []string, description string, run func() tea.Cmd) tea.Cmd {
+	for _, ws := range workspaces {
+		if m.cfg.IsProtected(ws) {
+			m.protectedAction = run
+			return m.confirmPrompt.Open(ws, description)
+		}
+	}
+	return run()
+}
+
+func (m *AppModel) handleConfirmKey(msg tea.KeyMsg) tea.Cmd {
+	_, cmd := m.confirmPrompt.Update(msg)
+
+	if m.confirmPrompt.Cancelled() {
+		m.protectedAction = nil
+		m.status = "Change cancelled"
+		return nil
+	}
+	if !m.confirmPrompt.Confirmed() {
+		return cmd
+	}
+
+	run := m.protectedAction
+	m.protectedAction = nil
+	return run()
+}
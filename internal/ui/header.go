@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/views"
+)
+
+// userResolvedMsg carries the user the server authenticated for a context.
+type userResolvedMsg struct {
+	context string
+	user    string
+}
+
+// resolveUserCmd asks the server who the user is once per context, since
+// the kubeconfig user name often says little about the actual identity.
+func (m *AppModel) resolveUserCmd() tea.Cmd {
+	name := m.clientMgr.ContextName()
+	if _, ok := m.users[name]; ok {
+		return nil
+	}
+	// Until the review returns, or if it fails, show the kubeconfig user.
+	m.users[name] = m.clientMgr.UserName()
+
+	cm := m.clientMgr
+	return func() tea.Msg {
+		user, err := cm.WhoAmI(context.Background())
+		if err != nil || user == "" {
+			return nil
+		}
+		return userResolvedMsg{context: name, user: user}
+	}
+}
+
+// viewName names the current view in the header.
+func (t *tabState) viewName() string {
+	switch t.state {
+	case StateAPIs:
+		return "APIs"
+	case StateSyncTargets:
+		return "SyncTargets"
+	case StateAvailableResources:
+		return "Resources"
+	case StateResourceInstances:
+		return "Instances of " + t.resourceInstanceList.GVR().Resource
+	case StateAuditLog:
+		return "Audit log"
+	case StateContextSelect:
+		return "Contexts"
+	}
+	return "Workspaces"
+}
+
+func (m *AppModel) headerView() string {
+	conn := m.clientMgr.ConnectionStatus()
+	info := views.HeaderInfo{
+		Context:      m.clientMgr.ContextName(),
+		Server:       m.clientMgr.BaseHost(),
+		User:         m.users[m.clientMgr.ContextName()],
+		Kind:         string(m.clientMgr.Kind()),
+		ReadOnly:     m.clientMgr.ReadOnly(),
+		Path:         m.clientMgr.CurrentWorkspace(),
+		Inaccessible: m.noAccess,
+		View:         m.viewName(),
+		Refreshed:    m.refreshedAt,
+		Latency:      conn.Latency,
+		Err:          conn.Err,
+		Tabs:         m.tabLabels(),
+		ActiveTab:    m.activeTab,
+	}
+	if m.clientMgr.Kind() == kcp.ClusterUnknown {
+		info.Kind = ""
+	}
+	m.header.SetInfo(info)
+	return m.header.View()
+}
+
+// markRefreshed records when the data of the current view was loaded.
+func (m *AppModel) markRefreshed(msg tea.Msg) {
+	switch msg.(type) {
+	case workspacesLoadedMsg, apisLoadedMsg, syncTargetsLoadedMsg, availableResourcesLoadedMsg, resourceInstancesLoadedMsg, auditLoadedMsg:
+		m.refreshedAt = time.Now()
+	}
+}
+
+// reportAuditFailure warns about a change that was made on the server but
+// could not be written to the audit log.
+func (m *AppModel) reportAuditFailure() {
+	log := m.clientMgr.AuditLog()
+	if log == nil {
+		return
+	}
+	if err := log.TakeError(); err != nil {
+		warning := fmt.Sprintf("Warning: change not recorded in the audit log: %v", err)
+		if m.status != "" {
+			warning = m.status + " | " + warning
+		}
+		m.status = warning
+	}
+}
+
+// handleMouse navigates to the workspace of a clicked breadcrumb and
+// switches to a clicked tab.
+func (m *AppModel) handleMouse(msg tea.MouseMsg) tea.Cmd {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return nil
+	}
+	if m.state == StateContextSelect || m.overlayActive() {
+		return nil
+	}
+	if tab, ok := m.header.TabAt(msg.X, msg.Y); ok {
+		return m.switchTab(tab - m.activeTab)
+	}
+	if m.loading || m.err != nil {
+		return nil
+	}
+
+	path, ok := m.header.PathAt(msg.X, msg.Y)
+	if !ok || (path == m.clientMgr.CurrentWorkspace() && m.state == StateWorkspaces) {
+		return nil
+	}
+	return m.goToWorkspace(path)
+}
@@ -0,0 +1,23 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/ui/views"
+)
+
+// openKeyHelp shows all key bindings.
+func (m *AppModel) openKeyHelp() tea.Cmd {
+	m.showingHelp = true
+	m.keyHelp.Update(m.windowSize)
+	m.keyHelp.Open()
+	return nil
+}
+
+func (m *AppModel) handleKeyHelpKey(msg tea.KeyMsg) tea.Cmd {
+	updated, cmd := m.keyHelp.Update(msg)
+	m.keyHelp = updated.(*views.KeyHelp)
+	if m.keyHelp.Closed() {
+		m.showingHelp = false
+	}
+	return cmd
+}
@@ -0,0 +1,425 @@
+// Package keys defines every key binding of kcplens in one place, so that
+// key handling, help texts and user overrides stay consistent.
+package keys
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// Scope is where a binding is active, as a set of places. Bindings whose
+// scopes overlap must not share a key.
+type Scope int
+
+const (
+	scopeBrowse Scope = 1 << iota
+	scopeObjects
+	scopeContexts
+	scopeDialogs
+	scopeEditDiff
+	scopeForms
+)
+
+const (
+	// ScopeLists bindings work in all list views.
+	ScopeLists = scopeBrowse | scopeObjects
+	// ScopeObjects bindings only work in the lists of API relationships
+	// and resource instances.
+	ScopeObjects = scopeObjects
+	// ScopeContexts bindings only work in the context selector.
+	ScopeContexts = scopeContexts
+	// ScopeDialogs bindings work in dialogs without text input, like the
+	// delete and apply confirmations and the bookmarks, history and help
+	// dialogs.
+	ScopeDialogs = scopeDialogs
+	// ScopeEditDiff bindings work in the diff shown after editing.
+	ScopeEditDiff = scopeEditDiff
+	// ScopeForms bindings work in forms with text input. They must not be
+	// keys that type a character.
+	ScopeForms = scopeForms
+	// ScopeAlways bindings work everywhere, including dialogs.
+	ScopeAlways = scopeBrowse | scopeObjects | scopeContexts | scopeDialogs | scopeEditDiff | scopeForms
+)
+
+func (s Scope) overlaps(other Scope) bool {
+	return s&other != 0
+}
+
+// KeyMap holds all key bindings.
+type KeyMap struct {
+	Quit      key.Binding
+	ForceQuit key.Binding
+	Help      key.Binding
+
+	Open        key.Binding
+	Back        key.Binding
+	APIs        key.Binding
+	SyncTargets key.Binding
+	Resources   key.Binding
+	AuditLog    key.Binding
+	Jump        key.Binding
+	Command     key.Binding
+	Contexts    key.Binding
+
+	Edit             key.Binding
+	Delete           key.Binding
+	NewWorkspace     key.Binding
+	Bind             key.Binding
+	ExportKubeconfig key.Binding
+	SyncKubeconfig   key.Binding
+
+	AddBookmark    key.Binding
+	Bookmarks      key.Binding
+	BookmarkSlots  key.Binding
+	HistoryBack    key.Binding
+	HistoryForward key.Binding
+	History        key.Binding
+
+	NewTab   key.Binding
+	NextTab  key.Binding
+	PrevTab  key.Binding
+	CloseTab key.Binding
+
+	Split         key.Binding
+	DetailMode    key.Binding
+	SplitNarrower key.Binding
+	SplitWider    key.Binding
+	DetailUp      key.Binding
+	DetailDown    key.Binding
+
+	ShowYAML key.Binding
+	Sort     key.Binding
+
+	SelectContext key.Binding
+	CancelContext key.Binding
+
+	Confirm     key.Binding
+	Cancel      key.Binding
+	Propagation key.Binding
+	DryRun      key.Binding
+	ToggleClaim key.Binding
+
+	Update          key.Binding
+	ServerSideApply key.Binding
+	ForceApply      key.Binding
+	EditAgain       key.Binding
+
+	Submit     key.Binding
+	NextField  key.Binding
+	PrevField  key.Binding
+	CancelForm key.Binding
+}
+
+func binding(help, desc string, keys ...string) key.Binding {
+	return key.NewBinding(key.WithKeys(keys...), key.WithHelp(help, desc))
+}
+
+// Default returns the built-in key bindings.
+func Default() *KeyMap {
+	return &KeyMap{
+		Quit:      binding("q", "Quit", "q"),
+		ForceQuit: binding("ctrl+c", "Quit, also while typing", "ctrl+c"),
+		Help:      binding("?", "Show key bindings", "?"),
+
+		Open:        binding("enter", "Open", "enter"),
+		Back:        binding("backspace/esc", "Back", "backspace", "esc"),
+		APIs:        binding("a", "APIs", "a"),
+		SyncTargets: binding("s", "SyncTargets", "s"),
+		Resources:   binding("r", "Resources", "r"),
+		AuditLog:    binding("A", "Audit log", "A"),
+		Jump:        binding("w", "Go to", "w"),
+		Command:     binding(":", "Command prompt", ":"),
+		Contexts:    binding("C", "Switch context", "C"),
+
+		Edit:             binding("e", "Edit", "e"),
+		Delete:           binding("ctrl+d", "Delete", "ctrl+d"),
+		NewWorkspace:     binding("n", "New", "n"),
+		Bind:             binding("b", "Bind export", "b"),
+		ExportKubeconfig: binding("x", "Kubeconfig", "x"),
+		SyncKubeconfig:   binding("K", "Toggle kubeconfig sync on exit", "K"),
+
+		AddBookmark:    binding("m", "Bookmark location", "m"),
+		Bookmarks:      binding("B", "Bookmarks", "B"),
+		BookmarkSlots:  binding("1-9", "Open bookmark slot", "1", "2", "3", "4", "5", "6", "7", "8", "9"),
+		HistoryBack:    binding("[/alt+←", "Go back in history", "[", "alt+left"),
+		HistoryForward: binding("]/alt+→", "Go forward in history", "]", "alt+right"),
+		History:        binding("H", "Navigation history", "H"),
+
+		NewTab:   binding("t", "Open selection in a new tab", "t"),
+		NextTab:  binding("tab", "Next tab", "tab"),
+		PrevTab:  binding("shift+tab", "Previous tab", "shift+tab"),
+		CloseTab: binding("ctrl+w", "Close tab", "ctrl+w"),
+
+		Split:         binding("v", "Toggle split layout", "v"),
+		DetailMode:    binding("V", "Cycle detail mode", "V"),
+		SplitNarrower: binding("<", "Narrower list", "<"),
+		SplitWider:    binding(">", "Wider list", ">"),
+		DetailUp:      binding("shift+↑", "Scroll detail up", "shift+up"),
+		DetailDown:    binding("shift+↓", "Scroll detail down", "shift+down"),
+
+		ShowYAML: binding("y", "Show YAML", "y"),
+		Sort:     binding("o", "Sort", "o"),
+
+		SelectContext: binding("enter", "Select", "enter"),
+		CancelContext: binding("esc", "Cancel", "esc"),
+
+		Confirm:     binding("y/enter", "Confirm", "y", "enter"),
+		Cancel:      binding("n/esc", "Cancel or close", "n", "esc", "backspace"),
+		Propagation: binding("p", "Propagation policy", "p", "tab"),
+		DryRun:      binding("d", "Toggle dry run", "d"),
+		ToggleClaim: binding("space", "Accept/reject claim", " ", "x"),
+
+		Update:          binding("y", "Update", "y"),
+		ServerSideApply: binding("s", "Server-side apply", "s"),
+		ForceApply:      binding("f", "Force server-side apply", "f"),
+		EditAgain:       binding("e", "Edit again", "e"),
+
+		Submit:     binding("enter", "Next field or submit", "enter"),
+		NextField:  binding("tab", "Next field", "tab", "down"),
+		PrevField:  binding("shift+tab", "Previous field", "shift+tab", "up"),
+		CancelForm: binding("esc", "Cancel", "esc"),
+	}
+}
+
+// action is a binding as it is named in the config file.
+type action struct {
+	name    string
+	group   string
+	scope   Scope
+	binding *key.Binding
+}
+
+func (k *KeyMap) actions() []action {
+	return []action{
+		{"quit", "General", ScopeAlways, &k.Quit},
+		{"forceQuit", "General", ScopeAlways, &k.ForceQuit},
+		{"help", "General", ScopeLists | ScopeDialogs, &k.Help},
+		{"command", "General", ScopeLists, &k.Command},
+		{"contexts", "General", ScopeLists, &k.Contexts},
+
+		{"open", "Navigation", ScopeLists, &k.Open},
+		{"back", "Navigation", ScopeLists, &k.Back},
+		{"apis", "Navigation", ScopeLists, &k.APIs},
+		{"syncTargets", "Navigation", ScopeLists, &k.SyncTargets},
+		{"resources", "Navigation", ScopeLists, &k.Resources},
+		{"auditLog", "Navigation", ScopeLists, &k.AuditLog},
+		{"jump", "Navigation", ScopeLists, &k.Jump},
+
+		{"edit", "Changes", ScopeLists, &k.Edit},
+		{"delete", "Changes", ScopeLists | ScopeDialogs, &k.Delete},
+		{"newWorkspace", "Changes", ScopeLists, &k.NewWorkspace},
+		{"bind", "Changes", ScopeLists, &k.Bind},
+		{"exportKubeconfig", "Changes", ScopeLists, &k.ExportKubeconfig},
+		{"syncKubeconfig", "Changes", ScopeLists, &k.SyncKubeconfig},
+
+		{"addBookmark", "Bookmarks and history", ScopeLists, &k.AddBookmark},
+		{"bookmarks", "Bookmarks and history", ScopeLists, &k.Bookmarks},
+		{"bookmarkSlots", "Bookmarks and history", ScopeLists, &k.BookmarkSlots},
+		{"historyBack", "Bookmarks and history", ScopeLists, &k.HistoryBack},
+		{"historyForward", "Bookmarks and history", ScopeLists, &k.HistoryForward},
+		{"history", "Bookmarks and history", ScopeLists | ScopeDialogs, &k.History},
+
+		{"newTab", "Tabs and layout", ScopeLists, &k.NewTab},
+		{"nextTab", "Tabs and layout", ScopeLists, &k.NextTab},
+		{"prevTab", "Tabs and layout", ScopeLists, &k.PrevTab},
+		{"closeTab", "Tabs and layout", ScopeLists, &k.CloseTab},
+		{"split", "Tabs and layout", ScopeLists, &k.Split},
+		{"detailMode", "Tabs and layout", ScopeLists, &k.DetailMode},
+		{"splitNarrower", "Tabs and layout", ScopeLists, &k.SplitNarrower},
+		{"splitWider", "Tabs and layout", ScopeLists, &k.SplitWider},
+		{"detailUp", "Tabs and layout", ScopeLists, &k.DetailUp},
+		{"detailDown", "Tabs and layout", ScopeLists, &k.DetailDown},
+
+		{"showYAML", "APIs and resource instances", ScopeObjects, &k.ShowYAML},
+		{"sort", "APIs and resource instances", ScopeObjects, &k.Sort},
+
+		{"selectContext", "Context selector", ScopeContexts, &k.SelectContext},
+		{"cancelContext", "Context selector", ScopeContexts, &k.CancelContext},
+
+		{"confirm", "Dialogs", ScopeDialogs, &k.Confirm},
+		{"cancel", "Dialogs", ScopeDialogs | ScopeEditDiff, &k.Cancel},
+		{"propagation", "Dialogs", ScopeDialogs, &k.Propagation},
+		{"dryRun", "Dialogs", ScopeDialogs, &k.DryRun},
+		{"toggleClaim", "Dialogs", ScopeDialogs, &k.ToggleClaim},
+
+		{"update", "Edited changes", ScopeEditDiff, &k.Update},
+		{"serverSideApply", "Edited changes", ScopeEditDiff, &k.ServerSideApply},
+		{"forceApply", "Edited changes", ScopeEditDiff, &k.ForceApply},
+		{"editAgain", "Edited changes", ScopeEditDiff, &k.EditAgain},
+
+		{"submit", "Forms", ScopeForms, &k.Submit},
+		{"nextField", "Forms", ScopeForms, &k.NextField},
+		{"prevField", "Forms", ScopeForms, &k.PrevField},
+		{"cancelForm", "Forms", ScopeForms, &k.CancelForm},
+	}
+}
+
+// New returns the default key bindings with overrides applied. overrides
+// maps action names to their new keys; an empty list disables the action.
+// Unknown actions and keys bound twice are reported as errors.
+func New(overrides map[string][]string) (*KeyMap, error) {
+	k := Default()
+	if err := k.override(overrides); err != nil {
+		return nil, err
+	}
+	if err := k.validate(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (k *KeyMap) override(overrides map[string][]string) error {
+	actions := make(map[string]*key.Binding)
+	for _, a := range k.actions() {
+		actions[a.name] = a.binding
+	}
+
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		b, ok := actions[name]
+		if !ok {
+			return fmt.Errorf("unknown key binding %q", name)
+		}
+		keys := overrides[name]
+		if name == "bookmarkSlots" && len(keys) > 9 {
+			return fmt.Errorf("bookmarkSlots takes at most 9 keys, got %d", len(keys))
+		}
+		if len(keys) == 0 {
+			// Without keys the binding is disabled.
+			b.SetKeys()
+			continue
+		}
+		b.SetKeys(keys...)
+		b.SetHelp(strings.Join(keys, "/"), b.Help().Desc)
+	}
+	return nil
+}
+
+// validate reports keys bound to more than one action in overlapping
+// scopes, keys taken by the navigation of the lists, which list views and
+// dialogs show, and form keys that would type a character.
+func (k *KeyMap) validate() error {
+	var errs []error
+	actions := k.actions()
+	reserved := reservedKeys()
+
+	for i, a := range actions {
+		for _, ks := range a.binding.Keys() {
+			if a.scope == ScopeForms && utf8.RuneCountInString(ks) == 1 {
+				errs = append(errs, fmt.Errorf("key %q of %s would be typed into forms", ks, a.name))
+			}
+			if owner, ok := reserved[ks]; ok && a.scope.overlaps(ScopeLists|ScopeDialogs) {
+				errs = append(errs, fmt.Errorf("key %q of %s is used by lists to %s", ks, a.name, owner))
+			}
+			for _, b := range actions[i+1:] {
+				if a.scope.overlaps(b.scope) && contains(b.binding.Keys(), ks) {
+					errs = append(errs, fmt.Errorf("key %q is bound to both %s and %s", ks, a.name, b.name))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func contains(keys []string, k string) bool {
+	for _, s := range keys {
+		if s == k {
+			return true
+		}
+	}
+	return false
+}
+
+// List returns the key bindings of the bubbles lists used by all views. The
+// lists do not quit or toggle their own help, which the application does,
+// and page with the arrow keys only so that letters stay free for actions.
+func List() list.KeyMap {
+	km := list.DefaultKeyMap()
+	km.PrevPage.SetKeys("left", "h", "pgup")
+	km.NextPage.SetKeys("right", "l", "pgdown")
+	km.Quit.SetKeys()
+	km.ForceQuit.SetKeys()
+	km.ShowFullHelp.SetKeys()
+	km.CloseFullHelp.SetKeys()
+	return km
+}
+
+// listNavigation returns the list bindings that are active while browsing.
+func listNavigation() []key.Binding {
+	km := List()
+	return []key.Binding{km.CursorUp, km.CursorDown, km.PrevPage, km.NextPage, km.GoToStart, km.GoToEnd, km.Filter}
+}
+
+// reservedKeys maps the keys used by list navigation to what they do.
+func reservedKeys() map[string]string {
+	reserved := make(map[string]string)
+	for _, b := range listNavigation() {
+		for _, k := range b.Keys() {
+			reserved[k] = b.Help().Desc
+		}
+	}
+	return reserved
+}
+
+// Group is a titled set of bindings shown together in the help overlay.
+type Group struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+// Groups returns all enabled bindings grouped for the help overlay,
+// followed by the list navigation keys.
+func (k *KeyMap) Groups() []Group {
+	var groups []Group
+	for _, a := range k.actions() {
+		if !a.binding.Enabled() {
+			continue
+		}
+		if len(groups) == 0 || groups[len(groups)-1].Title != a.group {
+			groups = append(groups, Group{Title: a.group})
+		}
+		last := &groups[len(groups)-1]
+		last.Bindings = append(last.Bindings, *a.binding)
+	}
+	return append(groups, Group{Title: "Lists", Bindings: listNavigation()})
+}
+
+// ShortHelp renders bindings as a help line like "[a] APIs  [q] Quit".
+// Disabled bindings are left out.
+func ShortHelp(bindings ...key.Binding) string {
+	parts := make([]string, 0, len(bindings))
+	for _, b := range bindings {
+		if b.Enabled() {
+			parts = append(parts, fmt.Sprintf("[%s] %s", b.Help().Key, b.Help().Desc))
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
+// As returns b with another description, for help lines where the generic
+// description of an action is too vague.
+func As(b key.Binding, desc string) key.Binding {
+	b.SetHelp(b.Help().Key, desc)
+	return b
+}
+
+// Slot returns the bookmark slot, 1 to 9, selected by the pressed key.
+func (k *KeyMap) Slot(pressed string) (int, bool) {
+	for i, ks := range k.BookmarkSlots.Keys() {
+		if ks == pressed {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
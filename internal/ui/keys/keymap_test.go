@@ -0,0 +1,144 @@
+package keys
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string][]string
+		wantErr   string
+		check     func(t *testing.T, k *KeyMap)
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, k *KeyMap) {
+				if got := k.NewTab.Keys(); len(got) != 1 || got[0] != "t" {
+					t.Errorf("NewTab keys = %v, want [t]", got)
+				}
+			},
+		},
+		{
+			name:      "override",
+			overrides: map[string][]string{"newTab": {"T", "ctrl+t"}},
+			check: func(t *testing.T, k *KeyMap) {
+				if got := strings.Join(k.NewTab.Keys(), ","); got != "T,ctrl+t" {
+					t.Errorf("NewTab keys = %s, want T,ctrl+t", got)
+				}
+				if got := k.NewTab.Help().Key; got != "T/ctrl+t" {
+					t.Errorf("NewTab help key = %q, want T/ctrl+t", got)
+				}
+			},
+		},
+		{
+			name:      "empty list disables",
+			overrides: map[string][]string{"syncKubeconfig": {}},
+			check: func(t *testing.T, k *KeyMap) {
+				if k.SyncKubeconfig.Enabled() {
+					t.Error("SyncKubeconfig is enabled, want disabled")
+				}
+			},
+		},
+		{
+			name:      "freed key can be reused",
+			overrides: map[string][]string{"newTab": {"T"}, "closeTab": {"t"}},
+			check: func(t *testing.T, k *KeyMap) {
+				if !contains(k.CloseTab.Keys(), "t") {
+					t.Errorf("CloseTab keys = %v, want t", k.CloseTab.Keys())
+				}
+			},
+		},
+		{
+			name:      "same key in scopes that do not overlap",
+			overrides: map[string][]string{"showYAML": {"x"}, "selectContext": {"x"}, "exportKubeconfig": {"X"}},
+		},
+		{
+			name:      "unknown action",
+			overrides: map[string][]string{"teleport": {"T"}},
+			wantErr:   `unknown key binding "teleport"`,
+		},
+		{
+			name:      "too many bookmark slots",
+			overrides: map[string][]string{"bookmarkSlots": {"1", "2", "3", "4", "5", "6", "7", "8", "9", "0"}},
+			wantErr:   "at most 9 keys",
+		},
+		{
+			name:      "conflict in the lists",
+			overrides: map[string][]string{"newTab": {"a"}},
+			wantErr:   `key "a" is bound to both apis and newTab`,
+		},
+		{
+			name:      "conflict with a binding active everywhere",
+			overrides: map[string][]string{"selectContext": {"q"}},
+			wantErr:   `key "q" is bound to both quit and selectContext`,
+		},
+		{
+			name:      "conflict between lists and objects",
+			overrides: map[string][]string{"sort": {"e"}},
+			wantErr:   `key "e" is bound to both edit and sort`,
+		},
+		{
+			name:      "conflict in dialogs",
+			overrides: map[string][]string{"cancel": {"H"}},
+			wantErr:   `key "H" is bound to both history and cancel`,
+		},
+		{
+			name:      "quit conflicts with a dialog key",
+			overrides: map[string][]string{"quit": {"y"}},
+			wantErr:   `key "y" is bound to both quit and confirm`,
+		},
+		{
+			name:      "quit conflicts with a key of the edited changes",
+			overrides: map[string][]string{"quit": {"s"}},
+			wantErr:   `key "s" is bound to both quit and serverSideApply`,
+		},
+		{
+			name:      "same key in different dialogs",
+			overrides: map[string][]string{"editAgain": {"d"}},
+		},
+		{
+			name:      "printable key in forms",
+			overrides: map[string][]string{"cancelForm": {"q"}},
+			wantErr:   `key "q" of cancelForm would be typed into forms`,
+		},
+		{
+			name:      "key reserved by the lists",
+			overrides: map[string][]string{"newTab": {"j"}},
+			wantErr:   `key "j" of newTab is used by lists`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, err := New(tt.overrides)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("New() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if tt.check != nil {
+				tt.check(t, k)
+			}
+		})
+	}
+}
+
+func TestSlot(t *testing.T) {
+	k, err := New(map[string][]string{"bookmarkSlots": {"f1", "f2", "f3"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if slot, ok := k.Slot("f2"); !ok || slot != 2 {
+		t.Errorf("Slot(f2) = %d, %v, want 2, true", slot, ok)
+	}
+	if _, ok := k.Slot("4"); ok {
+		t.Error("Slot(4) found a slot after the override")
+	}
+}
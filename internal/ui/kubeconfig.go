@@ -0,0 +1,40 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+)
+
+// SetSyncKubeconfig sets whether the current workspace is written back to
+// the kubeconfig when kcplens exits.
+func (m *AppModel) SetSyncKubeconfig(sync bool) {
+	m.syncKubeconfig = sync
+}
+
+// SyncKubeconfig reports whether the current workspace should be written
+// back to the kubeconfig on exit.
+func (m *AppModel) SyncKubeconfig() bool {
+	return m.syncKubeconfig && m.clientMgr.IsKCP()
+}
+
+// ClientManager returns the client of the selected context.
+func (m *AppModel) ClientManager() *kcp.ClientManager {
+	return m.clientMgr
+}
+
+func (m *AppModel) toggleSyncKubeconfig() tea.Cmd {
+	if m.listFiltering() {
+		return nil
+	}
+	if !m.clientMgr.IsKCP() {
+		m.status = "Context " + m.clientMgr.ContextName() + " is not a kcp server"
+		return nil
+	}
+	m.syncKubeconfig = !m.syncKubeconfig
+	if m.syncKubeconfig {
+		m.status = "The current workspace will be written to the kubeconfig on exit"
+	} else {
+		m.status = "The kubeconfig will be left unchanged on exit"
+	}
+	return nil
+}
@@ -0,0 +1,319 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/config"
+	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/views"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// maxNavigation bounds the back and forward stacks.
+const maxNavigation = 100
+
+// location is a place in kcplens that back and forward return to.
+type location struct {
+	state     AppState
+	context   string
+	workspace string
+	gvr       schema.GroupVersionResource
+	namespace string
+	selector  string
+	cursor    int
+}
+
+// same reports whether l and other are the same place, regardless of the
+// cursor.
+func (l location) same(other location) bool {
+	l.cursor, other.cursor = 0, 0
+	return l == other
+}
+
+func (l location) String() string {
+	where := l.workspace
+	if where == "" {
+		where = l.context
+	}
+
+	var s string
+	switch l.state {
+	case StateAPIs:
+		s = "APIs in " + where
+	case StateSyncTargets:
+		s = "SyncTargets in " + where
+	case StateAvailableResources:
+		s = "Resources in " + where
+	case StateAuditLog:
+		s = "Audit log"
+	case StateResourceInstances:
+		s = config.ResourceKey(l.gvr) + " in " + where
+		if l.namespace != "" {
+			s += " (namespace " + l.namespace + ")"
+		}
+		if l.selector != "" {
+			s += " [" + l.selector + "]"
+		}
+	default:
+		s = "Workspace " + where
+	}
+	return fmt.Sprintf("%s · %s", l.context, s)
+}
+
+// reopenResourceCmd lists the resource types of path again, so that going
+// back from the instances of gvr works, and then shows the instances.
+func reopenResourceCmd(cm *kcp.ClientManager, path string, gvr schema.GroupVersionResource) tea.Cmd {
+	return func() tea.Msg {
+		available, err := cm.DiscoverAvailableResources(context.Background(), path)
+		if err != nil {
+			return errorMsg{err}
+		}
+		return startResourceMsg{available: available, gvr: gvr}
+	}
+}
+
+// currentLocation describes what is shown right now.
+func (m *AppModel) currentLocation() location {
+	loc := location{
+		state:     m.state,
+		context:   m.clientMgr.ContextName(),
+		workspace: m.clientMgr.CurrentWorkspace(),
+	}
+
+	switch m.state {
+	case StateWorkspaces:
+		loc.cursor = m.workspaceList.Cursor()
+	case StateAPIs:
+		loc.cursor = m.apiList.Cursor()
+	case StateSyncTargets:
+		loc.cursor = m.syncTargetList.Cursor()
+	case StateAvailableResources:
+		loc.cursor = m.availableResourceList.Cursor()
+	case StateAuditLog:
+		loc.workspace = ""
+		loc.cursor = m.auditList.Cursor()
+	case StateResourceInstances:
+		loc.gvr = m.resourceInstanceList.GVR()
+		loc.namespace, loc.selector = m.resourceInstanceList.Scope()
+		loc.cursor = m.resourceInstanceList.Cursor()
+	}
+	return loc
+}
+
+func (m *AppModel) setCursor(state AppState, cursor int) {
+	switch state {
+	case StateWorkspaces:
+		m.workspaceList.SetCursor(cursor)
+	case StateAPIs:
+		m.apiList.SetCursor(cursor)
+	case StateSyncTargets:
+		m.syncTargetList.SetCursor(cursor)
+	case StateAvailableResources:
+		m.availableResourceList.SetCursor(cursor)
+	case StateAuditLog:
+		m.auditList.SetCursor(cursor)
+	case StateResourceInstances:
+		m.resourceInstanceList.SetCursor(cursor)
+	}
+}
+
+// trackLocation records a move to another location once it has loaded, so
+// that every way of getting somewhere is covered. Moves made by back and
+// forward only restore the cursor and take their stacks into effect.
+func (m *AppModel) trackLocation() {
+	if m.loading || m.err != nil || m.state == StateContextSelect || !m.sessionStarted {
+		return
+	}
+
+	cur := m.currentLocation()
+	if m.navigating {
+		m.navigating = false
+		m.setCursor(cur.state, m.pendingCursor)
+		cur.cursor = m.pendingCursor
+		if m.pendingNav != nil {
+			m.nav = *m.pendingNav
+			m.pendingNav = nil
+		}
+	} else if m.hasLocation && cur.same(m.lastLocation) {
+		m.lastLocation.cursor = cur.cursor
+		return
+	} else if m.hasLocation {
+		m.nav = m.nav.visit(m.lastLocation)
+	}
+	m.lastLocation = cur
+	m.hasLocation = true
+}
+
+// navStacks are the locations back and forward return to, the most recent
+// last. Its methods return new stacks and never modify the receiver, so that
+// a move that fails to load leaves the stacks as they were.
+type navStacks struct {
+	back    []location
+	forward []location
+}
+
+// visit returns the stacks after leaving current for a new location.
+func (s navStacks) visit(current location) navStacks {
+	return navStacks{back: pushLocation(s.back, current)}
+}
+
+// step returns the location step positions back (-1) or forward (+1) from
+// current and the stacks after moving there. ok is false if there is none.
+func (s navStacks) step(current location, step int) (target location, next navStacks, ok bool) {
+	from, to := s.back, s.forward
+	if step > 0 {
+		from, to = s.forward, s.back
+	}
+	if len(from) == 0 {
+		return location{}, s, false
+	}
+
+	target = from[len(from)-1]
+	from = from[: len(from)-1 : len(from)-1]
+	to = pushLocation(to, current)
+	if step > 0 {
+		return target, navStacks{back: to, forward: from}, true
+	}
+	return target, navStacks{back: from, forward: to}, true
+}
+
+// timeline returns all visited locations, oldest first, and the index of
+// current.
+func (s navStacks) timeline(current location) ([]location, int) {
+	all := append([]location(nil), s.back...)
+	index := len(all)
+	all = append(all, current)
+	for i := len(s.forward) - 1; i >= 0; i-- {
+		all = append(all, s.forward[i])
+	}
+	return all, index
+}
+
+// jumpStacks returns the stacks after moving to entry selected of a
+// timeline.
+func jumpStacks(timeline []location, selected int) navStacks {
+	s := navStacks{back: append([]location(nil), timeline[:selected]...)}
+	for i := len(timeline) - 1; i > selected; i-- {
+		s.forward = append(s.forward, timeline[i])
+	}
+	return s
+}
+
+// pushLocation appends loc to a copy of stack, dropping the oldest entries
+// beyond maxNavigation.
+func pushLocation(stack []location, loc location) []location {
+	stack = append(stack[:len(stack):len(stack)], loc)
+	if len(stack) > maxNavigation {
+		stack = stack[len(stack)-maxNavigation:]
+	}
+	return stack
+}
+
+// navigateHistory goes back (-1) or forward (+1) in the navigation history.
+func (m *AppModel) navigateHistory(step int) tea.Cmd {
+	if m.listFiltering() || !m.hasLocation {
+		return nil
+	}
+
+	target, next, ok := m.nav.step(m.lastLocation, step)
+	if !ok {
+		if step < 0 {
+			m.status = "No earlier location"
+		} else {
+			m.status = "No later location"
+		}
+		return nil
+	}
+	return m.openLocation(target, &next)
+}
+
+// openHistoryList lists all visited locations to jump to one of them.
+func (m *AppModel) openHistoryList() tea.Cmd {
+	if m.listFiltering() || !m.hasLocation {
+		return nil
+	}
+
+	all, current := m.nav.timeline(m.lastLocation)
+	entries := make([]string, len(all))
+	for i, loc := range all {
+		entries[i] = loc.String()
+	}
+
+	m.browsingHistory = true
+	m.historyList.Update(m.windowSize)
+	return m.historyList.Open(entries, current)
+}
+
+func (m *AppModel) handleHistoryListKey(msg tea.KeyMsg) tea.Cmd {
+	updated, cmd := m.historyList.Update(msg)
+	m.historyList = updated.(*views.HistoryList)
+
+	if m.historyList.Cancelled() {
+		m.browsingHistory = false
+		return nil
+	}
+	selected := m.historyList.Selected()
+	if selected < 0 {
+		return cmd
+	}
+
+	m.browsingHistory = false
+	all, current := m.nav.timeline(m.lastLocation)
+	if selected == current {
+		return nil
+	}
+
+	next := jumpStacks(all, selected)
+	return m.openLocation(all[selected], &next)
+}
+
+// openLocation shows loc again, switching the context if needed. The parent
+// workspaces become the path backspace walks up, as after a jump. next, if
+// not nil, replaces the back and forward stacks once loc has loaded.
+func (m *AppModel) openLocation(loc location, next *navStacks) tea.Cmd {
+	if loc.context != m.clientMgr.ContextName() {
+		if err := m.useContext(loc.context); err != nil {
+			m.status = fmt.Sprintf("Could not switch to context %s: %v", loc.context, err)
+			return nil
+		}
+	}
+
+	// The audit log does not belong to a workspace, so stay where we are.
+	workspace := loc.workspace
+	if loc.state == StateAuditLog {
+		workspace = m.clientMgr.CurrentWorkspace()
+	}
+
+	m.navigating = true
+	m.pendingCursor = loc.cursor
+	m.pendingNav = next
+	m.history = nil
+	if m.clientMgr.IsKCP() {
+		m.history = kcp.Ancestors(workspace)
+	}
+	m.clientMgr.SetWorkspace(workspace)
+	m.state = StateWorkspaces
+
+	switch loc.state {
+	case StateAPIs:
+		return m.handleAPIKey()
+	case StateSyncTargets:
+		return m.handleSyncTargetsKey()
+	case StateAvailableResources:
+		return m.handleResourcesKey()
+	case StateAuditLog:
+		return m.handleAuditKey()
+	case StateResourceInstances:
+		m.resourceInstanceList.SetScope(loc.namespace, loc.selector)
+		m.loading = true
+		return reopenResourceCmd(m.clientMgr, workspace, loc.gvr)
+	}
+
+	if !m.clientMgr.IsKCP() {
+		return m.handleResourcesKey()
+	}
+	m.loading = true
+	return fetchWorkspacesCmd(m.clientMgr, workspace)
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+)
+
+func loc(workspace string) location {
+	return location{state: StateWorkspaces, context: "kcp", workspace: workspace}
+}
+
+func workspaces(locs []location) string {
+	s := ""
+	for i, l := range locs {
+		if i > 0 {
+			s += ","
+		}
+		s += l.workspace
+	}
+	return s
+}
+
+func TestNavStacks(t *testing.T) {
+	var s navStacks
+	s = s.visit(loc("a"))
+	s = s.visit(loc("b"))
+	current := loc("c")
+
+	target, back, ok := s.step(current, -1)
+	if !ok || target.workspace != "b" {
+		t.Fatalf("step back = %v, %v, want b", target.workspace, ok)
+	}
+	if got := workspaces(back.back) + "|" + workspaces(back.forward); got != "a|c" {
+		t.Errorf("stacks after back = %s, want a|c", got)
+	}
+	if got := workspaces(s.back); got != "a,b" {
+		t.Errorf("step modified the receiver: back = %s", got)
+	}
+
+	target, forward, ok := back.step(target, 1)
+	if !ok || target.workspace != "c" {
+		t.Fatalf("step forward = %v, %v, want c", target.workspace, ok)
+	}
+	if got := workspaces(forward.back) + "|" + workspaces(forward.forward); got != "a,b|" {
+		t.Errorf("stacks after forward = %s, want a,b|", got)
+	}
+
+	if _, _, ok := forward.step(target, 1); ok {
+		t.Error("step forward without later locations succeeded")
+	}
+	if _, _, ok := (navStacks{}).step(current, -1); ok {
+		t.Error("step back without earlier locations succeeded")
+	}
+
+	// Visiting a new location drops the forward stack.
+	if s := back.visit(loc("b")); len(s.forward) != 0 {
+		t.Errorf("forward after visit = %s, want empty", workspaces(s.forward))
+	}
+}
+
+func TestNavStacksTimeline(t *testing.T) {
+	s := navStacks{back: []location{loc("a"), loc("b")}, forward: []location{loc("e"), loc("d")}}
+
+	all, current := s.timeline(loc("c"))
+	if got := workspaces(all); got != "a,b,c,d,e" || current != 2 {
+		t.Fatalf("timeline = %s at %d, want a,b,c,d,e at 2", got, current)
+	}
+
+	for selected, want := range []string{"|e,d,c,b", "a|e,d,c", "a,b|e,d", "a,b,c|e", "a,b,c,d|"} {
+		s := jumpStacks(all, selected)
+		if got := workspaces(s.back) + "|" + workspaces(s.forward); got != want {
+			t.Errorf("jumpStacks(%d) = %s, want %s", selected, got, want)
+		}
+	}
+}
+
+func TestPushLocationLimit(t *testing.T) {
+	var stack []location
+	for i := 0; i < maxNavigation+5; i++ {
+		stack = pushLocation(stack, loc(fmt.Sprint(i)))
+	}
+	if len(stack) != maxNavigation || stack[0].workspace != "5" {
+		t.Errorf("stack has %d entries starting at %s, want %d starting at 5", len(stack), stack[0].workspace, maxNavigation)
+	}
+
+	// Pushing onto a shared stack must not change the other copy.
+	base := pushLocation(nil, loc("a"))
+	base = append(base[:1:1], loc("b"))[:1]
+	one := pushLocation(base, loc("x"))
+	two := pushLocation(base, loc("y"))
+	if one[1].workspace != "x" || two[1].workspace != "y" {
+		t.Errorf("pushes share storage: %s and %s", workspaces(one), workspaces(two))
+	}
+}
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/config"
+	"github.com/peter/kcplens/internal/kcp"
+)
+
+// sessionCheckedMsg reports whether the workspace of a session to restore
+// can still be opened.
+type sessionCheckedMsg struct {
+	session config.Session
+	ok      bool
+}
+
+// SetSessions makes kcplens record where the user leaves off in each context
+// in sessions. With restore, the last session of a context is reopened when
+// the context is first used.
+func (m *AppModel) SetSessions(sessions *config.Sessions, restore bool) {
+	m.sessions = sessions
+	m.restoreSession = restore
+}
+
+// SaveSessions records where every tab left off in each context it visited
+// and writes the sessions. The active tab is recorded last, so that its
+// location wins for the context it shows.
+func (m *AppModel) SaveSessions() error {
+	if m.sessions == nil || !m.sessionStarted {
+		return nil
+	}
+
+	for i, t := range m.tabs {
+		if i == m.activeTab || !t.sessionStarted {
+			continue
+		}
+		t.recordContextStates(m.sessions)
+		m.sessions.Set(t.clientMgr.ContextName(), config.Session{Workspace: t.clientMgr.CurrentWorkspace(), History: t.history})
+	}
+
+	m.recordContextStates(m.sessions)
+	bm := m.currentBookmark()
+	m.sessions.Set(bm.Context, config.Session{
+		Workspace: bm.Workspace,
+		View:      bm.View,
+		Resource:  bm.Resource,
+		Namespace: bm.Namespace,
+		Selector:  bm.Selector,
+		History:   m.history,
+	})
+	return m.sessions.Save()
+}
+
+// recordContextStates stores the state of the contexts the tab has left.
+func (t *tabState) recordContextStates(sessions *config.Sessions) {
+	for name, state := range t.contextStates {
+		sessions.Set(name, config.Session{Workspace: state.workspace, History: state.history})
+	}
+}
+
+// takeSession returns the session to restore for the active context. Each
+// context is restored at most once, and never when a start location was
+// requested explicitly.
+func (m *AppModel) takeSession() (config.Session, bool) {
+	name := m.clientMgr.ContextName()
+	if m.restored[name] {
+		return config.Session{}, false
+	}
+	m.restored[name] = true
+
+	if !m.restoreSession || m.sessions == nil || m.startWorkspace != "" || m.startView != "" {
+		return config.Session{}, false
+	}
+	return m.sessions.Get(name)
+}
+
+// restoreSessionCmd checks that the workspace of session still exists
+// before reopening it. The session is only dropped if the workspace is gone,
+// other errors show up when it is opened.
+func (m *AppModel) restoreSessionCmd(session config.Session) tea.Cmd {
+	if !m.clientMgr.IsKCP() || session.Workspace == "" {
+		return m.applySession(session, false)
+	}
+
+	m.loading = true
+	cm := m.clientMgr
+	return func() tea.Msg {
+		exists, err := cm.WorkspaceExists(context.Background(), session.Workspace)
+		return sessionCheckedMsg{session: session, ok: exists || err != nil}
+	}
+}
+
+func (m *AppModel) handleSessionCheckedMsg(msg sessionCheckedMsg) tea.Cmd {
+	if !msg.ok {
+		m.status = fmt.Sprintf("Workspace %s of the last session is no longer available", msg.session.Workspace)
+		return m.startCmd()
+	}
+	return m.applySession(msg.session, true)
+}
+
+// applySession opens the location of session.
+func (m *AppModel) applySession(session config.Session, withHistory bool) tea.Cmd {
+	m.startWorkspace = session.Workspace
+	m.startView = session.View
+	if session.Resource != "" {
+		m.startView = session.Resource
+	}
+	m.resourceInstanceList.SetScope(session.Namespace, session.Selector)
+	if withHistory {
+		m.restoredHistory = validHistory(session.History)
+	}
+	return m.startCmd()
+}
+
+// validHistory drops malformed paths from a stored history.
+func validHistory(history []string) []string {
+	valid := make([]string, 0, len(history))
+	for _, path := range history {
+		if kcp.ValidateWorkspacePath(path) == nil {
+			valid = append(valid, path)
+		}
+	}
+	return valid
+}
@@ -0,0 +1,184 @@
+package ui
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/peter/kcplens/internal/ui/views"
+)
+
+const (
+	// minSplitWidth is the narrowest terminal the split layout is shown
+	// in. Below it the list takes the full width.
+	minSplitWidth     = 100
+	defaultSplitRatio = 0.5
+	minSplitRatio     = 0.25
+	maxSplitRatio     = 0.75
+	splitRatioStep    = 0.05
+)
+
+// detailSource is a list whose highlighted item can be shown in the
+// detail pane.
+type detailSource interface {
+	Detail() (string, interface{})
+	Cursor() int
+}
+
+// detailKey identifies what the detail pane shows. The pane is only
+// rendered again when it changes, not on every message.
+type detailKey struct {
+	tab     int
+	state   AppState
+	cursor  int
+	title   string
+	mode    views.DetailMode
+	version time.Time
+}
+
+// SetSplit enables the split layout at startup. ratio is the share of the
+// width taken by the list, 0 uses the default.
+func (m *AppModel) SetSplit(split bool, ratio float64) {
+	m.split = split
+	if ratio > 0 {
+		m.splitRatio = clampRatio(ratio)
+	}
+}
+
+func clampRatio(ratio float64) float64 {
+	return min(max(ratio, minSplitRatio), maxSplitRatio)
+}
+
+// splitActive reports whether the split layout is shown, which needs it
+// to be enabled and the terminal to be wide enough.
+func (m *AppModel) splitActive() bool {
+	return m.split && m.windowSize.Width >= minSplitWidth && m.detailSource() != nil
+}
+
+// paneWidths returns the width of the list and of the detail pane.
+func (m *AppModel) paneWidths() (int, int) {
+	ratio := m.splitRatio
+	if ratio == 0 {
+		ratio = defaultSplitRatio
+	}
+	left := int(float64(m.windowSize.Width) * ratio)
+	return left, m.windowSize.Width - left
+}
+
+func (m *AppModel) detailSource() detailSource {
+	switch m.state {
+	case StateWorkspaces:
+		return m.workspaceList
+	case StateAPIs:
+		if m.apiList.InDetailView() {
+			return nil
+		}
+		return m.apiList
+	case StateSyncTargets:
+		return m.syncTargetList
+	case StateAvailableResources:
+		return m.availableResourceList
+	case StateResourceInstances:
+		if m.resourceInstanceList.InDetailView() {
+			return nil
+		}
+		return m.resourceInstanceList
+	case StateAuditLog:
+		return m.auditList
+	}
+	return nil
+}
+
+// layoutPanes sizes the list views to the width left for them.
+func (m *AppModel) layoutPanes() {
+	if m.windowSize.Width == 0 {
+		return
+	}
+	size := m.windowSize
+	if m.split && size.Width >= minSplitWidth {
+		left, right := m.paneWidths()
+		size.Width = left
+		m.detailPane.SetSize(right, views.DetailPaneHeight(size.Height))
+	}
+	m.workspaceList.Update(size)
+	m.apiList.Update(size)
+	m.syncTargetList.Update(size)
+	m.availableResourceList.Update(size)
+	m.resourceInstanceList.Update(size)
+	m.auditList.Update(size)
+}
+
+// refreshDetail shows the highlighted item of the current list in the
+// detail pane, unless it is shown already. The time the list was last
+// loaded stands for the version of its data.
+func (m *AppModel) refreshDetail() {
+	if !m.splitActive() || m.loading || m.err != nil {
+		return
+	}
+	source := m.detailSource()
+	title, obj := source.Detail()
+	key := detailKey{
+		tab:     m.tabID,
+		state:   m.state,
+		cursor:  source.Cursor(),
+		title:   title,
+		mode:    m.detailPane.Mode(),
+		version: m.refreshedAt,
+	}
+	if key == m.detailKey {
+		return
+	}
+	m.detailKey = key
+	m.detailPane.Show(title, obj)
+}
+
+// withDetail places the detail pane to the right of view.
+func (m *AppModel) withDetail(view string) string {
+	if !m.splitActive() {
+		return view
+	}
+	left, _ := m.paneWidths()
+	view = lipgloss.NewStyle().Width(left).MaxWidth(left).Render(view)
+	return lipgloss.JoinHorizontal(lipgloss.Top, view, m.detailPane.View())
+}
+
+func (m *AppModel) toggleSplit() tea.Cmd {
+	if m.listFiltering() {
+		return nil
+	}
+	m.split = !m.split
+	m.layoutPanes()
+	if m.split && m.windowSize.Width < minSplitWidth {
+		m.status = "Split layout enabled, the terminal is too narrow to show it"
+	}
+	return nil
+}
+
+func (m *AppModel) cycleDetailMode() tea.Cmd {
+	if m.listFiltering() || !m.splitActive() {
+		return nil
+	}
+	m.detailPane.SetMode(m.detailPane.Mode().Next())
+	return nil
+}
+
+// resizeSplit moves the border between the panes by step.
+func (m *AppModel) resizeSplit(step float64) tea.Cmd {
+	if m.listFiltering() || !m.splitActive() {
+		return nil
+	}
+	ratio := m.splitRatio
+	if ratio == 0 {
+		ratio = defaultSplitRatio
+	}
+	m.splitRatio = clampRatio(ratio + step)
+	m.layoutPanes()
+	return nil
+}
+
+func (m *AppModel) scrollDetail(lines int) tea.Cmd {
+	if m.splitActive() {
+		m.detailPane.ScrollDown(lines)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Start views accepted by SetStart besides a resource type.
+const (
+	StartViewWorkspaces  = "workspaces"
+	StartViewAPIs        = "apis"
+	StartViewResources   = "resources"
+	StartViewSyncTargets = "synctargets"
+)
+
+type ancestorAccessMsg struct {
+	denied []string
+}
+
+type workspaceDeniedMsg struct {
+	path    string
+	from    string
+	skipped []string
+	err     error
+}
+
+type startResourceMsg struct {
+	available []kcp.AvailableResource
+	gvr       schema.GroupVersionResource
+}
+
+// SetStart makes kcplens open view in workspace instead of the workspace
+// list of root. view is one of the StartView constants or a resource type,
+// in which case its instances are listed.
+func (m *AppModel) SetStart(workspace, view string) {
+	m.startWorkspace = workspace
+	m.startView = view
+}
+
+// startCmd loads the start workspace, which defaults to the workspace
+// selected in the kubeconfig. Its ancestors become the history so that going
+// back works as if the user had navigated there. They are probed in the
+// background since users often lack access to root or their organization.
+// The user of a context is resolved on its first start. A client whose kind
+// of server is not known yet is probed first.
+func (m *AppModel) startCmd() tea.Cmd {
+	if !m.clientMgr.Probed() {
+		m.loading = true
+		return probeClusterCmd(m.clientMgr)
+	}
+	return tea.Batch(m.resolveUserCmd(), m.startLocationCmd())
+}
+
+func (m *AppModel) startLocationCmd() tea.Cmd {
+	if session, ok := m.takeSession(); ok {
+		return m.restoreSessionCmd(session)
+	}
+	if !m.clientMgr.IsKCP() {
+		return m.startPlainCmd()
+	}
+
+	path := m.startWorkspace
+	if path == "" {
+		path = m.clientMgr.InitialWorkspace()
+	}
+	m.startWorkspace = ""
+	m.history = kcp.Ancestors(path)
+	if m.restoredHistory != nil {
+		m.history = m.restoredHistory
+		m.restoredHistory = nil
+	}
+	m.noAccess = make(map[string]bool)
+
+	m.clientMgr.SetWorkspace(path)
+	m.loading = true
+	return tea.Batch(fetchWorkspacesCmd(m.clientMgr, path), probeAncestorsCmd(m.clientMgr, m.history), m.indexWorkspacesCmd())
+}
+
+func probeAncestorsCmd(cm *kcp.ClientManager, ancestors []string) tea.Cmd {
+	if len(ancestors) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		var denied []string
+		for _, path := range ancestors {
+			// Other errors are left to the navigation itself to report.
+			if ok, err := cm.CanListWorkspaces(context.Background(), path); err == nil && !ok {
+				denied = append(denied, path)
+			}
+		}
+		return ancestorAccessMsg{denied}
+	}
+}
+
+// fetchParentWorkspacesCmd lists a workspace the user navigates back to.
+// Missing access is reported separately so the user stays where they are.
+func fetchParentWorkspacesCmd(cm *kcp.ClientManager, path, from string, skipped []string) tea.Cmd {
+	return func() tea.Msg {
+		ws, err := cm.DiscoverWorkspaces(context.Background(), path)
+		if kcp.IsAccessDenied(err) {
+			return workspaceDeniedMsg{path: path, from: from, skipped: skipped, err: err}
+		}
+		if err != nil {
+			return errorMsg{err}
+		}
+		return workspacesLoadedMsg{ws}
+	}
+}
+
+// navigateBack returns to the nearest workspace in the history that is not
+// known to be inaccessible.
+func (m *AppModel) navigateBack() tea.Cmd {
+	target := -1
+	for i := len(m.history) - 1; i >= 0; i-- {
+		if !m.noAccess[m.history[i]] {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		if len(m.history) > 0 {
+			m.status = "No access to the parent workspaces"
+		}
+		return nil
+	}
+
+	from := m.clientMgr.CurrentWorkspace()
+	prev := m.history[target]
+	skipped := append([]string(nil), m.history[target:]...)
+	m.history = m.history[:target]
+
+	m.loading = true
+	m.clientMgr.SetWorkspace(prev)
+	return fetchParentWorkspacesCmd(m.clientMgr, prev, from, skipped)
+}
+
+func (m *AppModel) handleAccessMsg(msg tea.Msg) tea.Cmd {
+	switch msg := msg.(type) {
+	case ancestorAccessMsg:
+		for _, path := range msg.denied {
+			m.noAccess[path] = true
+		}
+
+	case workspaceDeniedMsg:
+		m.loading = false
+		m.noAccess[msg.path] = true
+		m.history = append(m.history, msg.skipped...)
+		m.clientMgr.SetWorkspace(msg.from)
+		m.status = fmt.Sprintf("No access to workspace %s", msg.path)
+	}
+	return nil
+}
+
+// openStartView switches to the requested start view once the start
+// workspace is loaded.
+func (m *AppModel) openStartView() tea.Cmd {
+	view := m.startView
+	m.startView = ""
+
+	switch strings.ToLower(view) {
+	case "", StartViewWorkspaces:
+		return nil
+	case StartViewAPIs:
+		return m.handleAPIKey()
+	case StartViewResources:
+		return m.handleResourcesKey()
+	case StartViewSyncTargets:
+		return m.handleSyncTargetsKey()
+	}
+
+	m.loading = true
+	return resolveStartResourceCmd(m.clientMgr, m.clientMgr.CurrentWorkspace(), view)
+}
+
+func resolveStartResourceCmd(cm *kcp.ClientManager, path, arg string) tea.Cmd {
+	return func() tea.Msg {
+		available, err := cm.DiscoverAvailableResources(context.Background(), path)
+		if err != nil {
+			return errorMsg{err}
+		}
+		gvr, err := kcp.MatchResource(available, path, arg)
+		if err != nil {
+			return errorMsg{err}
+		}
+		return startResourceMsg{available: available, gvr: gvr}
+	}
+}
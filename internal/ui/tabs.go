@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/keys"
+	"github.com/peter/kcplens/internal/ui/views"
+)
+
+// tabMsg carries a message produced by a command of the tab with id, so
+// that results arriving after a tab switch update the tab that asked for
+// them.
+type tabMsg struct {
+	id  int
+	msg tea.Msg
+}
+
+// runtimeMsg carries a message for Bubble Tea itself, such as running a
+// process, which must reach it untagged. See runtimeCmd.
+type runtimeMsg struct {
+	msg tea.Msg
+}
+
+// runtimeCmd marks cmd as addressed to Bubble Tea, so that tagCmd passes
+// its message on as it is.
+func runtimeCmd(cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		return runtimeMsg{cmd()}
+	}
+}
+
+// tagCmd makes the messages of cmd, including those of batched commands,
+// arrive as tabMsg for the tab with id. Only tea.QuitMsg, shared messages
+// and those of commands marked with runtimeCmd are left untagged.
+func tagCmd(cmd tea.Cmd, id int) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case nil:
+			return nil
+		case tea.BatchMsg:
+			tagged := make(tea.BatchMsg, len(msg))
+			for i, c := range msg {
+				tagged[i] = tagCmd(c, id)
+			}
+			return tagged
+		case tea.QuitMsg:
+			return msg
+		case runtimeMsg:
+			return msg.msg
+		default:
+			if sharedMsg(msg) {
+				return msg
+			}
+			return tabMsg{id: id, msg: msg}
+		}
+	}
+}
+
+// sharedMsg reports whether msg updates state shared by all tabs, which
+// must be applied even if the tab that asked for it has been closed.
+func sharedMsg(msg tea.Msg) bool {
+	switch msg.(type) {
+	case userResolvedMsg, contextKindsMsg:
+		return true
+	}
+	return false
+}
+
+// headerHeight returns the lines taken by the header, which shows a tab bar
+// once there is more than one tab.
+func (m *AppModel) headerHeight() int {
+	if len(m.tabs) > 1 {
+		return views.HeaderHeight + 1
+	}
+	return views.HeaderHeight
+}
+
+// resize lays out the views for the current terminal size, for instance
+// after the header changed its height.
+func (m *AppModel) resize() {
+	if m.termSize.Width == 0 {
+		return
+	}
+	size := m.termSize
+	size.Height -= m.headerHeight()
+	m.update(size)
+	m.layoutPanes()
+}
+
+func newTabState(cm *kcp.ClientManager, km *keys.KeyMap, state AppState) tabState {
+	return tabState{
+		clientMgr:             cm,
+		workspaceList:         views.NewWorkspaceList(km),
+		apiList:               views.NewAPIList(km),
+		syncTargetList:        views.NewSyncTargetList(km),
+		availableResourceList: views.NewAvailableResourceList(km),
+		resourceInstanceList:  views.NewResourceInstanceList(km),
+		auditList:             views.NewAuditList(km),
+		state:                 state,
+		history:               []string{},
+		noAccess:              make(map[string]bool),
+		contextStates:         make(map[string]navState),
+		workspaceIndexes:      make(map[string]*workspaceIndex),
+		restored:              make(map[string]bool),
+	}
+}
+
+func (m *AppModel) tabIndex(id int) int {
+	for i, t := range m.tabs {
+		if t.tabID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// activateTab makes the tab at index the one shown, keeping the state of
+// the previously active tab.
+func (m *AppModel) activateTab(index int) {
+	*m.tabs[m.activeTab] = m.tabState
+	m.activeTab = index
+	m.tabState = *m.tabs[index]
+}
+
+// updateTab handles a message for the tab it was produced by. Messages of
+// background tabs are applied to their state without showing them.
+func (m *AppModel) updateTab(msg tabMsg) tea.Cmd {
+	index := m.tabIndex(msg.id)
+	if index < 0 {
+		// The tab has been closed in the meantime.
+		return nil
+	}
+	if index == m.activeTab {
+		_, cmd := m.Update(msg.msg)
+		return cmd
+	}
+
+	active := m.activeTab
+	m.activateTab(index)
+	_, cmd := m.update(msg.msg)
+	m.markRefreshed(msg.msg)
+	m.trackLocation()
+	m.activateTab(active)
+	return tagCmd(cmd, msg.id)
+}
+
+// selectionLocation is where a new tab opens: the highlighted workspace,
+// the workspace of the export behind the highlighted binding, the instances
+// of the highlighted resource type, or else the current location.
+func (m *AppModel) selectionLocation() location {
+	loc := m.currentLocation()
+	loc.cursor = 0
+
+	switch m.state {
+	case StateWorkspaces:
+		if node := m.workspaceList.SelectedNode(); node != nil {
+			loc.workspace = node.Path
+		}
+	case StateAPIs:
+		if rel := m.apiList.SelectedRelationship(); rel != nil && rel.Type == "Binding" && rel.ExportPath != "" {
+			loc.workspace = rel.ExportPath
+		}
+	case StateAvailableResources:
+		if res := m.availableResourceList.SelectedResource(); res != nil {
+			loc.state = StateResourceInstances
+			loc.gvr = res.GVR
+		}
+	}
+	return loc
+}
+
+// openTab opens the current selection in a new tab next to the others.
+func (m *AppModel) openTab() tea.Cmd {
+	if m.listFiltering() {
+		return nil
+	}
+
+	loc := m.selectionLocation()
+	cm, err := m.clientMgr.Clone()
+	if err != nil {
+		m.status = fmt.Sprintf("Could not open a tab: %v", err)
+		return nil
+	}
+
+	tab := newTabState(cm, m.keys, StateWorkspaces)
+	tab.tabID = m.nextTabID
+	tab.sessionStarted = true
+	// The context is already in use, so its last session is not restored
+	// again, and the workspaces found so far need not be crawled again.
+	context := cm.ContextName()
+	tab.restored[context] = true
+	if idx, ok := m.workspaceIndexes[context]; ok {
+		tab.workspaceIndexes[context] = idx.clone(cm)
+	}
+	m.tabs = append(m.tabs, &tab)
+	m.nextTabID++
+	m.activateTab(len(m.tabs) - 1)
+	m.resize()
+	return m.openLocation(loc, nil)
+}
+
+// switchTab activates the tab step positions away, wrapping around.
+func (m *AppModel) switchTab(step int) tea.Cmd {
+	if len(m.tabs) < 2 {
+		return nil
+	}
+	m.activateTab((m.activeTab + step + len(m.tabs)) % len(m.tabs))
+	m.layoutPanes()
+	return m.indexWorkspacesCmd()
+}
+
+// closeTab closes the active tab. The last tab cannot be closed.
+func (m *AppModel) closeTab() tea.Cmd {
+	if len(m.tabs) < 2 {
+		m.status = "Cannot close the last tab"
+		return nil
+	}
+
+	closed := m.activeTab
+	m.tabs = append(m.tabs[:closed], m.tabs[closed+1:]...)
+	m.activeTab = min(closed, len(m.tabs)-1)
+	m.tabState = *m.tabs[m.activeTab]
+	m.resize()
+	return m.indexWorkspacesCmd()
+}
+
+// handleTabKey switches and closes tabs. It works while a view is still
+// loading, so that a slow workspace does not block the other tabs.
+func (m *AppModel) handleTabKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if m.overlayActive() || m.listFiltering() {
+		return nil, false
+	}
+	switch {
+	case key.Matches(msg, m.keys.NextTab):
+		return m.switchTab(1), true
+	case key.Matches(msg, m.keys.PrevTab):
+		return m.switchTab(-1), true
+	case key.Matches(msg, m.keys.CloseTab):
+		return m.closeTab(), true
+	}
+	return nil, false
+}
+
+// tabLabels names every tab by its location and view.
+func (m *AppModel) tabLabels() []string {
+	labels := make([]string, len(m.tabs))
+	for i, t := range m.tabs {
+		if i == m.activeTab {
+			t = &m.tabState
+		}
+		where := t.location()
+		if idx := strings.LastIndex(where, ":"); idx >= 0 {
+			where = where[idx+1:]
+		}
+		labels[i] = fmt.Sprintf("%d %s · %s", i+1, where, t.viewName())
+	}
+	return labels
+}
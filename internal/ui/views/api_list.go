@@ -3,11 +3,13 @@ package views
 import (
 	"fmt"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/keys"
 	"sigs.k8s.io/yaml"
 )
 
@@ -26,17 +28,18 @@ func (i APIItem) Title() string {
 }
 
 func (i APIItem) Description() string {
+	terminating := terminatingInfo(i.rel.Deleting, i.rel.Finalizers)
 	if i.rel.Type == "Binding" {
 		path := i.rel.ExportPath
 		if path == "" {
 			path = "unknown"
 		}
-		return fmt.Sprintf("from: %s | status: %s", path, i.rel.Status)
+		return fmt.Sprintf("from: %s | status: %s%s", path, i.rel.Status, terminating)
 	}
 	if i.rel.Type == "Export" {
-		return fmt.Sprintf("provides API to consumers | status: %s", i.rel.Status)
+		return fmt.Sprintf("provides API to consumers | status: %s%s", i.rel.Status, terminating)
 	}
-	return fmt.Sprintf("status: %s", i.rel.Status)
+	return fmt.Sprintf("status: %s%s", i.rel.Status, terminating)
 }
 
 func (i APIItem) FilterValue() string {
@@ -51,19 +54,23 @@ const (
 )
 
 type APIList struct {
-	list     list.Model
+	cursorList
 	viewport viewport.Model
 	state    APIListViewState
 	ready    bool
+	keys     *keys.KeyMap
 }
 
-func NewAPIList() *APIList {
+func NewAPIList(km *keys.KeyMap) *APIList {
 	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.KeyMap = keys.List()
 	l.Title = "API Relationships"
 	l.SetShowStatusBar(false)
 	return &APIList{
-		list:  l,
-		state: APIListStateList,
+		cursorList: cursorList{l},
+		viewport:   viewport.New(0, 0),
+		state:      APIListStateList,
+		keys:       km,
 	}
 }
 
@@ -90,8 +97,8 @@ func (a *APIList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "y":
+		switch {
+		case key.Matches(msg, a.keys.ShowYAML) && a.list.FilterState() != list.Filtering:
 			if a.state == APIListStateList {
 				if item, ok := a.list.SelectedItem().(APIItem); ok {
 					yamlBytes, err := yaml.Marshal(item.rel.Raw)
@@ -108,7 +115,8 @@ func (a *APIList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		h, v := docStyle.GetFrameSize()
 		a.list.SetSize(msg.Width-h, msg.Height-v)
-		a.viewport = viewport.New(msg.Width-h, msg.Height-v-2)
+		a.viewport.Width = msg.Width - h
+		a.viewport.Height = msg.Height - v - 2
 		a.ready = true
 	}
 
@@ -128,12 +136,13 @@ func (a *APIList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (a *APIList) View() string {
 	if a.state == APIListStateDetail {
-		title := lipgloss.NewStyle().Bold(true).Margin(1, 2, 0, 2).Render("YAML (press backspace/esc to go back)")
-		help := helpStyle.Render("[backspace/esc] Back  [q] Quit")
+		title := lipgloss.NewStyle().Bold(true).Margin(1, 2, 0, 2).Render(fmt.Sprintf("YAML (press %s to go back)", a.keys.Back.Help().Key))
+		help := helpStyle.Render(keys.ShortHelp(a.keys.Back, a.keys.Quit))
 		return title + "\n" + docStyle.Render(a.viewport.View()) + "\n" + help
 	}
 
-	help := helpStyle.Render("[y] Show YAML  [backspace/esc] Back  [q] Quit")
+	k := a.keys
+	help := helpStyle.Render(keys.ShortHelp(k.ShowYAML, k.Edit, k.Bind, k.Delete, k.Back, keys.As(k.Help, "Help"), k.Quit))
 	return docStyle.Render(a.list.View()) + "\n" + help
 }
 
@@ -148,6 +157,11 @@ func (a *APIList) SelectedRelationship() *kcp.APIRelationship {
 	return nil
 }
 
+// Filtering reports whether the user is typing a filter query.
+func (a *APIList) Filtering() bool {
+	return a.list.FilterState() == list.Filtering
+}
+
 func (a *APIList) InDetailView() bool {
 	return a.state == APIListStateDetail
 }
@@ -155,3 +169,12 @@ func (a *APIList) InDetailView() bool {
 func (a *APIList) ExitDetailView() {
 	a.state = APIListStateList
 }
+
+// Detail returns the highlighted relationship for the detail pane.
+func (a *APIList) Detail() (string, interface{}) {
+	item, ok := a.list.SelectedItem().(APIItem)
+	if !ok || item.rel.Raw == nil {
+		return "", nil
+	}
+	return item.Title(), item.rel.Raw
+}
@@ -0,0 +1,103 @@
+package views
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/audit"
+	"github.com/peter/kcplens/internal/ui/keys"
+)
+
+type AuditItem struct {
+	entry audit.Entry
+}
+
+func (i AuditItem) Title() string {
+	name := i.entry.Name
+	if i.entry.Namespace != "" {
+		name = i.entry.Namespace + "/" + name
+	}
+	title := fmt.Sprintf("%s %s %s", i.entry.Verb, i.entry.GVR, name)
+	if i.entry.DryRun {
+		title += " (dry run)"
+	}
+	if i.entry.Error != "" {
+		title += " (failed)"
+	}
+	return title
+}
+
+func (i AuditItem) Description() string {
+	desc := fmt.Sprintf("%s | %s | %s | %s",
+		i.entry.Timestamp.Local().Format("2006-01-02 15:04:05"), i.entry.Context, i.entry.User, i.entry.Workspace)
+	if i.entry.Diff != "" {
+		desc += " | " + i.entry.Diff
+	}
+	if i.entry.Error != "" {
+		desc += " | " + i.entry.Error
+	}
+	return desc
+}
+
+func (i AuditItem) FilterValue() string {
+	return i.entry.Verb + " " + i.entry.GVR + " " + i.entry.Name + " " + i.entry.Workspace + " " + i.entry.Context
+}
+
+// AuditList browses the local audit log, newest entries first.
+type AuditList struct {
+	cursorList
+	keys *keys.KeyMap
+}
+
+func NewAuditList(km *keys.KeyMap) *AuditList {
+	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.KeyMap = keys.List()
+	l.Title = "Audit Log"
+	l.SetShowStatusBar(false)
+	return &AuditList{cursorList: cursorList{l}, keys: km}
+}
+
+func (a *AuditList) SetItems(entries []audit.Entry, path string) tea.Cmd {
+	a.list.Title = fmt.Sprintf("Audit Log (%s)", path)
+	items := make([]list.Item, len(entries))
+	for i, e := range entries {
+		items[i] = AuditItem{entry: e}
+	}
+	return a.list.SetItems(items)
+}
+
+func (a *AuditList) Init() tea.Cmd {
+	return nil
+}
+
+func (a *AuditList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := docStyle.GetFrameSize()
+		a.list.SetSize(msg.Width-h, msg.Height-v)
+	}
+
+	var cmd tea.Cmd
+	a.list, cmd = a.list.Update(msg)
+	return a, cmd
+}
+
+func (a *AuditList) View() string {
+	help := helpStyle.Render(keys.ShortHelp(keys.As(a.list.KeyMap.Filter, "Filter"), a.keys.Back, keys.As(a.keys.Help, "Help"), a.keys.Quit))
+	return docStyle.Render(a.list.View()) + "\n" + help
+}
+
+// Detail returns the highlighted entry for the detail pane.
+func (a *AuditList) Detail() (string, interface{}) {
+	item, ok := a.list.SelectedItem().(AuditItem)
+	if !ok {
+		return "", nil
+	}
+	return item.Title(), item.entry
+}
+
+// Filtering reports whether the user is typing a filter query.
+func (a *AuditList) Filtering() bool {
+	return a.list.FilterState() == list.Filtering
+}
@@ -2,12 +2,18 @@ package views
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/peter/kcplens/internal/config"
 	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/keys"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/yaml"
 )
@@ -29,21 +35,27 @@ func (i AvailableResourceItem) Description() string {
 	if i.res.Namespaced {
 		scope = "namespaced"
 	}
-	return fmt.Sprintf("GVR: %s/%s | %s", i.res.GVR.GroupVersion(), i.res.GVR.Resource, scope)
+	desc := fmt.Sprintf("GVR: %s/%s | %s", i.res.GVR.GroupVersion(), i.res.GVR.Resource, scope)
+	if len(i.res.ShortNames) > 0 {
+		desc += " | Short: " + strings.Join(i.res.ShortNames, ", ")
+	}
+	return desc
 }
 
 func (i AvailableResourceItem) FilterValue() string {
-	return i.res.Kind + " " + i.res.GVR.Resource + " " + i.res.GVR.Group
+	return i.res.Kind + " " + i.res.GVR.Resource + " " + i.res.GVR.Group + " " + strings.Join(i.res.ShortNames, " ")
 }
 
 type AvailableResourceList struct {
-	list list.Model
+	cursorList
+	keys *keys.KeyMap
 }
 
-func NewAvailableResourceList() *AvailableResourceList {
+func NewAvailableResourceList(km *keys.KeyMap) *AvailableResourceList {
 	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.KeyMap = keys.List()
 	l.Title = "Available Resources"
-	return &AvailableResourceList{list: l}
+	return &AvailableResourceList{cursorList: cursorList{l}, keys: km}
 }
 
 func (a *AvailableResourceList) SetItems(resources []kcp.AvailableResource) tea.Cmd {
@@ -71,7 +83,7 @@ func (a *AvailableResourceList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (a *AvailableResourceList) View() string {
-	help := helpStyle.Render("[enter] List instances  [backspace/esc] Back  [q] Quit")
+	help := helpStyle.Render(keys.ShortHelp(keys.As(a.keys.Open, "List instances"), a.keys.Back, keys.As(a.keys.Help, "Help"), a.keys.Quit))
 	return docStyle.Render(a.list.View()) + "\n" + help
 }
 
@@ -86,6 +98,10 @@ func (a *AvailableResourceList) SelectedResource() *kcp.AvailableResource {
 	return nil
 }
 
+func (a *AvailableResourceList) Filtering() bool {
+	return a.list.FilterState() == list.Filtering
+}
+
 func (a *AvailableResourceList) Title() string {
 	return a.list.Title
 }
@@ -95,7 +111,9 @@ func (a *AvailableResourceList) SetTitle(title string) {
 }
 
 type ResourceListItem struct {
-	res kcp.GenericResource
+	res     kcp.GenericResource
+	columns []config.Column
+	values  []string
 }
 
 func (i ResourceListItem) Title() string {
@@ -107,7 +125,11 @@ func (i ResourceListItem) Description() string {
 	if ns == "" {
 		ns = "-"
 	}
-	return fmt.Sprintf("Namespace: %s | Workspace: %s", ns, i.res.Workspace)
+	desc := fmt.Sprintf("Namespace: %s | Workspace: %s", ns, i.res.Workspace)
+	for idx, col := range i.columns {
+		desc += fmt.Sprintf(" | %s: %s", col.Name, i.values[idx])
+	}
+	return desc + terminatingInfo(i.res.Deleting, i.res.Finalizers)
 }
 
 func (i ResourceListItem) FilterValue() string {
@@ -115,27 +137,57 @@ func (i ResourceListItem) FilterValue() string {
 }
 
 type ResourceInstanceList struct {
-	list     list.Model
+	cursorList
 	gvr      schema.GroupVersionResource
 	viewport viewport.Model
 	state    APIListViewState
+	title    string
+
+	// namespace and selector limit the listed instances.
+	namespace string
+	selector  string
+
+	columns []config.Column
+	items   []ResourceListItem
+	// sortColumn is the index into columns used for ordering, -1 sorts by name.
+	sortColumn int
+	sortDesc   bool
+
+	keys *keys.KeyMap
 }
 
-func NewResourceInstanceList() *ResourceInstanceList {
+func NewResourceInstanceList(km *keys.KeyMap) *ResourceInstanceList {
 	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.KeyMap = keys.List()
 	l.Title = "Resources"
 	return &ResourceInstanceList{
-		list:  l,
-		state: APIListStateList,
+		cursorList: cursorList{l},
+		viewport:   viewport.New(0, 0),
+		state:      APIListStateList,
+		sortColumn: -1,
+		keys:       km,
 	}
 }
 
 func (r *ResourceInstanceList) SetItems(resources []kcp.GenericResource) tea.Cmd {
-	items := make([]list.Item, len(resources))
+	r.items = make([]ResourceListItem, len(resources))
 	for i, res := range resources {
-		items[i] = ResourceListItem{res: res}
+		values := make([]string, len(r.columns))
+		for c, col := range r.columns {
+			values[c] = col.Value(res.Raw)
+		}
+		r.items[i] = ResourceListItem{res: res, columns: r.columns, values: values}
 	}
-	return r.list.SetItems(items)
+	return r.applySort()
+}
+
+// SetColumns sets the user-defined columns for the current GVR and resets
+// the sort order to the resource name.
+func (r *ResourceInstanceList) SetColumns(columns []config.Column) {
+	r.columns = columns
+	r.sortColumn = -1
+	r.sortDesc = false
+	r.updateTitle()
 }
 
 func (r *ResourceInstanceList) SetGVR(gvr schema.GroupVersionResource) {
@@ -144,7 +196,91 @@ func (r *ResourceInstanceList) SetGVR(gvr schema.GroupVersionResource) {
 	if group == "" {
 		group = "core"
 	}
-	r.list.Title = fmt.Sprintf("%s (%s.%s)", gvr.Resource, gvr.Resource, group)
+	r.title = fmt.Sprintf("%s (%s.%s)", gvr.Resource, gvr.Resource, group)
+	r.updateTitle()
+}
+
+// SetScope limits the listed instances to a namespace and a label selector.
+// Empty values list everything.
+func (r *ResourceInstanceList) SetScope(namespace, selector string) {
+	r.namespace = namespace
+	r.selector = selector
+	r.updateTitle()
+}
+
+func (r *ResourceInstanceList) Scope() (string, string) {
+	return r.namespace, r.selector
+}
+
+func (r *ResourceInstanceList) updateTitle() {
+	title := r.title
+	if r.namespace != "" {
+		title += " in " + r.namespace
+	}
+	if r.selector != "" {
+		title += " with " + r.selector
+	}
+	if r.sortColumn < 0 || r.sortColumn >= len(r.columns) {
+		r.list.Title = title
+		return
+	}
+	dir := "asc"
+	if r.sortDesc {
+		dir = "desc"
+	}
+	r.list.Title = fmt.Sprintf("%s sorted by %s (%s)", title, r.columns[r.sortColumn].Name, dir)
+}
+
+// cycleSort advances to the next sort key: name ascending, name descending,
+// then each custom column in both directions.
+func (r *ResourceInstanceList) cycleSort() tea.Cmd {
+	if !r.sortDesc {
+		r.sortDesc = true
+	} else {
+		r.sortDesc = false
+		r.sortColumn++
+		if r.sortColumn >= len(r.columns) {
+			r.sortColumn = -1
+		}
+	}
+	r.updateTitle()
+	return r.applySort()
+}
+
+func (r *ResourceInstanceList) applySort() tea.Cmd {
+	sorted := make([]ResourceListItem, len(r.items))
+	copy(sorted, r.items)
+
+	col := r.sortColumn
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if r.sortDesc {
+			a, b = b, a
+		}
+		if col >= 0 && col < len(r.columns) && a.values[col] != b.values[col] {
+			return lessValue(a.values[col], b.values[col])
+		}
+		if a.res.Namespace != b.res.Namespace {
+			return a.res.Namespace < b.res.Namespace
+		}
+		return a.res.Name < b.res.Name
+	})
+
+	items := make([]list.Item, len(sorted))
+	for i, item := range sorted {
+		items[i] = item
+	}
+	return r.list.SetItems(items)
+}
+
+// lessValue compares numerically when both values are numbers.
+func lessValue(a, b string) bool {
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return fa < fb
+	}
+	return a < b
 }
 
 func (r *ResourceInstanceList) GVR() schema.GroupVersionResource {
@@ -158,8 +294,13 @@ func (r *ResourceInstanceList) Init() tea.Cmd {
 func (r *ResourceInstanceList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "y":
+		filtering := r.list.FilterState() == list.Filtering
+		switch {
+		case key.Matches(msg, r.keys.Sort) && !filtering:
+			if r.state == APIListStateList {
+				return r, r.cycleSort()
+			}
+		case key.Matches(msg, r.keys.ShowYAML) && !filtering:
 			if r.state == APIListStateList {
 				if item, ok := r.list.SelectedItem().(ResourceListItem); ok {
 					yamlBytes, err := yaml.Marshal(item.res.Raw)
@@ -176,7 +317,8 @@ func (r *ResourceInstanceList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		h, v := docStyle.GetFrameSize()
 		r.list.SetSize(msg.Width-h, msg.Height-v)
-		r.viewport = viewport.New(msg.Width-h, msg.Height-v-2)
+		r.viewport.Width = msg.Width - h
+		r.viewport.Height = msg.Height - v - 2
 	}
 
 	switch r.state {
@@ -195,18 +337,35 @@ func (r *ResourceInstanceList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (r *ResourceInstanceList) View() string {
 	if r.state == APIListStateDetail {
-		title := lipgloss.NewStyle().Bold(true).Margin(1, 2, 0, 2).Render("YAML (press backspace/esc to go back)")
-		help := helpStyle.Render("[backspace/esc] Back  [q] Quit")
+		title := lipgloss.NewStyle().Bold(true).Margin(1, 2, 0, 2).Render(fmt.Sprintf("YAML (press %s to go back)", r.keys.Back.Help().Key))
+		help := helpStyle.Render(keys.ShortHelp(r.keys.Back, r.keys.Quit))
 		return title + "\n" + docStyle.Render(r.viewport.View()) + "\n" + help
 	}
 
-	help := helpStyle.Render("[y] Show YAML  [backspace/esc] Back to resource types  [q] Quit")
+	k := r.keys
+	help := helpStyle.Render(keys.ShortHelp(k.ShowYAML, k.Edit, k.Sort, k.Delete, keys.As(k.Back, "Back to resource types"), keys.As(k.Help, "Help"), k.Quit))
 	return docStyle.Render(r.list.View()) + "\n" + help
 }
 
 func (r *ResourceInstanceList) SetWorkspacePath(path string) {
 }
 
+func (r *ResourceInstanceList) SelectedResource() *kcp.GenericResource {
+	i := r.list.SelectedItem()
+	if i == nil {
+		return nil
+	}
+	if ri, ok := i.(ResourceListItem); ok {
+		return &ri.res
+	}
+	return nil
+}
+
+// Filtering reports whether the user is typing a filter query.
+func (r *ResourceInstanceList) Filtering() bool {
+	return r.list.FilterState() == list.Filtering
+}
+
 func (r *ResourceInstanceList) InDetailView() bool {
 	return r.state == APIListStateDetail
 }
@@ -214,3 +373,35 @@ func (r *ResourceInstanceList) InDetailView() bool {
 func (r *ResourceInstanceList) ExitDetailView() {
 	r.state = APIListStateList
 }
+
+// Detail returns the highlighted resource type for the detail pane.
+func (a *AvailableResourceList) Detail() (string, interface{}) {
+	item, ok := a.list.SelectedItem().(AvailableResourceItem)
+	if !ok {
+		return "", nil
+	}
+	res := item.res
+	return item.Title(), map[string]interface{}{
+		"kind":         res.Kind,
+		"group":        res.GVR.Group,
+		"version":      res.GVR.Version,
+		"resource":     res.GVR.Resource,
+		"singularName": res.SingularName,
+		"shortNames":   res.ShortNames,
+		"categories":   res.Categories,
+		"namespaced":   res.Namespaced,
+	}
+}
+
+// Detail returns the highlighted instance for the detail pane.
+func (r *ResourceInstanceList) Detail() (string, interface{}) {
+	item, ok := r.list.SelectedItem().(ResourceListItem)
+	if !ok {
+		return "", nil
+	}
+	name := item.res.Name
+	if item.res.Namespace != "" {
+		name = item.res.Namespace + "/" + name
+	}
+	return name, item.res.Raw
+}
@@ -0,0 +1,255 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/keys"
+)
+
+type bindStep int
+
+const (
+	bindStepExport bindStep = iota
+	bindStepTarget
+	bindStepClaims
+)
+
+type ExportItem struct {
+	export kcp.APIExportRef
+}
+
+func (i ExportItem) Title() string { return i.export.Name }
+func (i ExportItem) Description() string {
+	return fmt.Sprintf("from: %s | permission claims: %d", i.export.Path, len(i.export.Claims))
+}
+func (i ExportItem) FilterValue() string { return i.export.Name + " " + i.export.Path }
+
+// BindWizard walks through binding an APIExport: choosing the export (when
+// started from the catalog), the target workspace and the permission claims.
+type BindWizard struct {
+	keys      *keys.KeyMap
+	step      bindStep
+	exports   list.Model
+	export    kcp.APIExportRef
+	target    textinput.Model
+	accepted  []bool
+	cursor    int
+	submitted bool
+	cancelled bool
+}
+
+func NewBindWizard(km *keys.KeyMap) *BindWizard {
+	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.KeyMap = keys.List()
+	l.Title = "APIExport Catalog"
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+
+	target := textinput.New()
+	target.Prompt = ""
+	target.Placeholder = "root:org:team"
+	target.ShowSuggestions = true
+
+	return &BindWizard{keys: km, exports: l, target: target}
+}
+
+func (b *BindWizard) reset(targetPath string, suggestions []string) {
+	b.submitted = false
+	b.cancelled = false
+	b.cursor = 0
+	b.target.SetValue(targetPath)
+	b.target.SetSuggestions(suggestions)
+	b.target.CursorEnd()
+}
+
+// OpenForExport starts the wizard for a known export at the target step.
+func (b *BindWizard) OpenForExport(export kcp.APIExportRef, targetPath string, suggestions []string) tea.Cmd {
+	b.reset(targetPath, suggestions)
+	b.selectExport(export)
+	b.step = bindStepTarget
+	return b.target.Focus()
+}
+
+// OpenCatalog starts the wizard with a list of exports to choose from.
+func (b *BindWizard) OpenCatalog(exports []kcp.APIExportRef, targetPath string, suggestions []string) tea.Cmd {
+	b.reset(targetPath, suggestions)
+	b.target.Blur()
+	b.step = bindStepExport
+	items := make([]list.Item, len(exports))
+	for i, e := range exports {
+		items[i] = ExportItem{export: e}
+	}
+	return b.exports.SetItems(items)
+}
+
+func (b *BindWizard) selectExport(export kcp.APIExportRef) {
+	b.export = export
+	b.accepted = make([]bool, len(export.Claims))
+	for i := range b.accepted {
+		b.accepted[i] = true
+	}
+}
+
+func (b *BindWizard) Submitted() bool { return b.submitted }
+func (b *BindWizard) Cancelled() bool { return b.cancelled }
+
+// TextInputActive reports whether keys are currently typed into a field.
+func (b *BindWizard) TextInputActive() bool {
+	return b.step == bindStepTarget || b.exports.FilterState() == list.Filtering
+}
+
+// TargetWorkspace returns the workspace the export is bound into.
+func (b *BindWizard) TargetWorkspace() string {
+	return strings.TrimSpace(b.target.Value())
+}
+
+// Binding returns the APIBinding described by the wizard.
+func (b *BindWizard) Binding() kcp.NewAPIBinding {
+	return kcp.NewAPIBinding{
+		Name:     b.export.Name,
+		Export:   b.export,
+		Accepted: b.accepted,
+	}
+}
+
+func (b *BindWizard) Init() tea.Cmd {
+	return nil
+}
+
+func (b *BindWizard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := formStyle.GetFrameSize()
+		b.exports.SetSize(msg.Width-h, msg.Height-v-4)
+		return b, nil
+	case tea.KeyMsg:
+		switch b.step {
+		case bindStepExport:
+			return b, b.updateExportStep(msg)
+		case bindStepTarget:
+			return b, b.updateTargetStep(msg)
+		case bindStepClaims:
+			b.updateClaimsStep(msg)
+			return b, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch b.step {
+	case bindStepExport:
+		b.exports, cmd = b.exports.Update(msg)
+	case bindStepTarget:
+		b.target, cmd = b.target.Update(msg)
+	}
+	return b, cmd
+}
+
+func (b *BindWizard) updateExportStep(msg tea.KeyMsg) tea.Cmd {
+	if b.exports.FilterState() != list.Filtering {
+		switch {
+		case key.Matches(msg, b.keys.Cancel):
+			// The key that clears an applied filter does that first.
+			if b.exports.FilterState() == list.FilterApplied && key.Matches(msg, b.exports.KeyMap.ClearFilter) {
+				break
+			}
+			b.cancelled = true
+			return nil
+		case key.Matches(msg, b.keys.Confirm):
+			if item, ok := b.exports.SelectedItem().(ExportItem); ok {
+				b.selectExport(item.export)
+				b.step = bindStepTarget
+				return b.target.Focus()
+			}
+			return nil
+		}
+	}
+
+	var cmd tea.Cmd
+	b.exports, cmd = b.exports.Update(msg)
+	return cmd
+}
+
+func (b *BindWizard) updateTargetStep(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, b.keys.CancelForm):
+		b.cancelled = true
+		return nil
+	case key.Matches(msg, b.keys.Submit):
+		if b.TargetWorkspace() == "" {
+			return nil
+		}
+		b.target.Blur()
+		if len(b.export.Claims) == 0 {
+			b.submitted = true
+			return nil
+		}
+		b.step = bindStepClaims
+		return nil
+	}
+
+	var cmd tea.Cmd
+	b.target, cmd = b.target.Update(msg)
+	return cmd
+}
+
+func (b *BindWizard) updateClaimsStep(msg tea.KeyMsg) {
+	nav := keys.List()
+	switch {
+	case key.Matches(msg, b.keys.Cancel):
+		b.step = bindStepTarget
+		b.target.Focus()
+	case key.Matches(msg, nav.CursorUp):
+		if b.cursor > 0 {
+			b.cursor--
+		}
+	case key.Matches(msg, nav.CursorDown):
+		if b.cursor < len(b.accepted)-1 {
+			b.cursor++
+		}
+	case key.Matches(msg, b.keys.ToggleClaim):
+		b.accepted[b.cursor] = !b.accepted[b.cursor]
+	case key.Matches(msg, b.keys.Confirm):
+		b.submitted = true
+	}
+}
+
+func (b *BindWizard) View() string {
+	if b.step == bindStepExport {
+		help := helpStyle.Render(keys.ShortHelp(keys.As(b.keys.Confirm, "Choose export"), keys.As(b.exports.KeyMap.Filter, "Filter"), keys.As(b.keys.Cancel, "Cancel")))
+		return docStyle.Render(b.exports.View()) + "\n" + help
+	}
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "Bind APIExport %s\nfrom %s\n\n",
+		lipgloss.NewStyle().Bold(true).Render(b.export.Name), b.export.Path)
+
+	if b.step == bindStepTarget {
+		fmt.Fprintf(&s, "%s\n    %s", focusedLabelStyle.Render("> Target workspace"), b.target.View())
+		help := helpStyle.Render("[tab] Complete  " + keys.ShortHelp(keys.As(b.keys.Submit, "Continue"), b.keys.CancelForm))
+		return formStyle.Render(s.String()) + "\n" + help
+	}
+
+	fmt.Fprintf(&s, "Into workspace %s\n\n", lipgloss.NewStyle().Bold(true).Render(b.TargetWorkspace()))
+	s.WriteString("Permission claims:\n")
+	for i, claim := range b.export.Claims {
+		cursor := "  "
+		if i == b.cursor {
+			cursor = focusedLabelStyle.Render("> ")
+		}
+		check := "[ ] reject"
+		if b.accepted[i] {
+			check = "[x] accept"
+		}
+		fmt.Fprintf(&s, "%s%s  %s\n", cursor, check, claim)
+	}
+
+	help := helpStyle.Render(keys.ShortHelp(b.keys.ToggleClaim, keys.As(b.keys.Confirm, "Create binding"), keys.As(b.keys.Cancel, "Back")))
+	return formStyle.Render(strings.TrimRight(s.String(), "\n")) + "\n" + help
+}
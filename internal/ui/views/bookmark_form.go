@@ -0,0 +1,146 @@
+package views
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/peter/kcplens/internal/config"
+)
+
+var errorTextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+const (
+	bookmarkFieldName = iota
+	bookmarkFieldSlot
+	bookmarkFieldCount
+)
+
+// BookmarkForm asks for the name and the optional number key of a new
+// bookmark.
+type BookmarkForm struct {
+	bookmark  config.Bookmark
+	name      textinput.Model
+	slot      textinput.Model
+	focus     int
+	err       string
+	submitted bool
+	cancelled bool
+}
+
+func NewBookmarkForm() *BookmarkForm {
+	name := textinput.New()
+	name.Prompt = ""
+
+	slot := textinput.New()
+	slot.Prompt = ""
+	slot.Placeholder = "none"
+	slot.CharLimit = 1
+
+	return &BookmarkForm{name: name, slot: slot}
+}
+
+// Open resets the form for bm, whose name is suggested.
+func (f *BookmarkForm) Open(bm config.Bookmark) tea.Cmd {
+	f.bookmark = bm
+	f.focus = bookmarkFieldName
+	f.err = ""
+	f.submitted = false
+	f.cancelled = false
+	f.name.SetValue(bm.Name)
+	f.name.CursorEnd()
+	f.slot.Reset()
+	f.slot.Blur()
+	return f.name.Focus()
+}
+
+func (f *BookmarkForm) Submitted() bool { return f.submitted }
+func (f *BookmarkForm) Cancelled() bool { return f.cancelled }
+
+// Bookmark returns the bookmark with the entered name and slot.
+func (f *BookmarkForm) Bookmark() config.Bookmark {
+	bm := f.bookmark
+	bm.Name = strings.TrimSpace(f.name.Value())
+	bm.Slot, _ = strconv.Atoi(strings.TrimSpace(f.slot.Value()))
+	return bm
+}
+
+func (f *BookmarkForm) validate() string {
+	if strings.TrimSpace(f.name.Value()) == "" {
+		return "Name is required"
+	}
+	if slot := strings.TrimSpace(f.slot.Value()); slot != "" {
+		if n, err := strconv.Atoi(slot); err != nil || n < 1 || n > 9 {
+			return "Key must be a digit from 1 to 9"
+		}
+	}
+	return ""
+}
+
+func (f *BookmarkForm) setFocus(field int) tea.Cmd {
+	f.focus = (field + bookmarkFieldCount) % bookmarkFieldCount
+	f.name.Blur()
+	f.slot.Blur()
+	if f.focus == bookmarkFieldName {
+		return f.name.Focus()
+	}
+	return f.slot.Focus()
+}
+
+func (f *BookmarkForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (f *BookmarkForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			f.cancelled = true
+			return f, nil
+		case "tab", "down":
+			return f, f.setFocus(f.focus + 1)
+		case "shift+tab", "up":
+			return f, f.setFocus(f.focus - 1)
+		case "enter":
+			if f.focus < bookmarkFieldSlot {
+				return f, f.setFocus(f.focus + 1)
+			}
+			f.err = f.validate()
+			f.submitted = f.err == ""
+			return f, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch f.focus {
+	case bookmarkFieldName:
+		f.name, cmd = f.name.Update(msg)
+	case bookmarkFieldSlot:
+		f.slot, cmd = f.slot.Update(msg)
+	}
+	return f, cmd
+}
+
+func (f *BookmarkForm) label(field int, text string) string {
+	if f.focus == field {
+		return focusedLabelStyle.Render("> " + text)
+	}
+	return "  " + text
+}
+
+func (f *BookmarkForm) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Bookmark %s\n\n", DescribeBookmark(f.bookmark))
+	fmt.Fprintf(&b, "%s\n    %s\n\n", f.label(bookmarkFieldName, "Name"), f.name.View())
+	fmt.Fprintf(&b, "%s\n    %s", f.label(bookmarkFieldSlot, "Number key (1-9, optional)"), f.slot.View())
+	if f.err != "" {
+		fmt.Fprintf(&b, "\n\n%s", errorTextStyle.Render(f.err))
+	}
+
+	help := helpStyle.Render("[tab/enter] Next field  [enter] Save (on last field)  [esc] Cancel")
+	return formStyle.Render(b.String()) + "\n" + help
+}
@@ -0,0 +1,164 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/config"
+	"github.com/peter/kcplens/internal/ui/keys"
+)
+
+type BookmarkItem struct {
+	bm config.Bookmark
+}
+
+func (i BookmarkItem) Title() string {
+	title := i.bm.Name
+	if i.bm.Slot != 0 {
+		title = fmt.Sprintf("[%d] %s", i.bm.Slot, title)
+	}
+	if i.bm.Shared {
+		title += " (shared)"
+	}
+	return title
+}
+
+func (i BookmarkItem) Description() string {
+	return DescribeBookmark(i.bm)
+}
+
+func (i BookmarkItem) FilterValue() string {
+	return i.bm.Name + " " + i.bm.Workspace + " " + i.bm.Resource
+}
+
+// DescribeBookmark summarizes where a bookmark leads.
+func DescribeBookmark(bm config.Bookmark) string {
+	parts := []string{"Context: " + valueOr(bm.Context, "current")}
+	if bm.Workspace != "" {
+		parts = append(parts, "Workspace: "+bm.Workspace)
+	}
+	if bm.Resource != "" {
+		parts = append(parts, "Resource: "+bm.Resource)
+	} else {
+		parts = append(parts, "View: "+valueOr(bm.View, "workspaces"))
+	}
+	if bm.Namespace != "" {
+		parts = append(parts, "Namespace: "+bm.Namespace)
+	}
+	if bm.Selector != "" {
+		parts = append(parts, "Selector: "+bm.Selector)
+	}
+	return strings.Join(parts, " | ")
+}
+
+func valueOr(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// BookmarkList is the bookmarks panel.
+type BookmarkList struct {
+	list      list.Model
+	keys      *keys.KeyMap
+	selected  *config.Bookmark
+	removed   *config.Bookmark
+	cancelled bool
+}
+
+func NewBookmarkList(km *keys.KeyMap) *BookmarkList {
+	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.KeyMap = keys.List()
+	l.Title = "Bookmarks"
+	l.SetShowHelp(false)
+	return &BookmarkList{list: l, keys: km}
+}
+
+// Open shows bookmarks and resets the panel.
+func (b *BookmarkList) Open(bookmarks []config.Bookmark) tea.Cmd {
+	b.selected = nil
+	b.removed = nil
+	b.cancelled = false
+	b.list.ResetFilter()
+	return b.SetItems(bookmarks)
+}
+
+func (b *BookmarkList) SetItems(bookmarks []config.Bookmark) tea.Cmd {
+	items := make([]list.Item, len(bookmarks))
+	for i, bm := range bookmarks {
+		items[i] = BookmarkItem{bm: bm}
+	}
+	return b.list.SetItems(items)
+}
+
+// Selected returns the bookmark chosen with enter, if any.
+func (b *BookmarkList) Selected() *config.Bookmark { return b.selected }
+
+// Removed returns the bookmark the user asked to delete, if any.
+func (b *BookmarkList) Removed() *config.Bookmark {
+	removed := b.removed
+	b.removed = nil
+	return removed
+}
+
+func (b *BookmarkList) Cancelled() bool { return b.cancelled }
+
+func (b *BookmarkList) Filtering() bool {
+	return b.list.FilterState() == list.Filtering
+}
+
+func (b *BookmarkList) Init() tea.Cmd {
+	return nil
+}
+
+func (b *BookmarkList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if b.Filtering() {
+			break
+		}
+		switch {
+		case key.Matches(msg, b.keys.Cancel):
+			// The key that clears an applied filter does that first.
+			if b.list.FilterState() == list.FilterApplied && key.Matches(msg, b.list.KeyMap.ClearFilter) {
+				break
+			}
+			b.cancelled = true
+			return b, nil
+		case key.Matches(msg, b.keys.Confirm):
+			if item, ok := b.list.SelectedItem().(BookmarkItem); ok {
+				b.selected = &item.bm
+			}
+			return b, nil
+		case key.Matches(msg, b.keys.Delete):
+			if item, ok := b.list.SelectedItem().(BookmarkItem); ok && !item.bm.Shared {
+				b.removed = &item.bm
+			}
+			return b, nil
+		}
+	case tea.WindowSizeMsg:
+		h, v := docStyle.GetFrameSize()
+		b.list.SetSize(msg.Width-h, msg.Height-v-2)
+	}
+
+	var cmd tea.Cmd
+	b.list, cmd = b.list.Update(msg)
+	return b, cmd
+}
+
+func (b *BookmarkList) View() string {
+	var s strings.Builder
+	s.WriteString(docStyle.Render(b.list.View()))
+	s.WriteString("\n")
+	if len(b.list.Items()) == 0 {
+		s.WriteString(emptyStyle.Render(fmt.Sprintf("No bookmarks yet. Press [%s] anywhere to bookmark the current view.", b.keys.AddBookmark.Help().Key)))
+		s.WriteString("\n")
+	}
+	k := b.keys
+	s.WriteString(helpStyle.Render(keys.ShortHelp(keys.As(k.Confirm, "Open"), keys.As(b.list.KeyMap.Filter, "Filter"), k.Delete, keys.As(k.Cancel, "Close"), k.Quit)))
+	return s.String()
+}
@@ -0,0 +1,94 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var commandPromptStyle = lipgloss.NewStyle().Margin(0, 2)
+
+var commandSuggestionStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("241")).
+	Margin(0, 2)
+
+// maxShownSuggestions limits the completions listed below the prompt.
+const maxShownSuggestions = 5
+
+// CommandPrompt reads a single command line, started with ':'.
+type CommandPrompt struct {
+	input     textinput.Model
+	submitted bool
+	cancelled bool
+}
+
+func NewCommandPrompt() *CommandPrompt {
+	input := textinput.New()
+	input.Prompt = ":"
+	input.Placeholder = "ws <path> | apis | st | res | <resource> | ctx <name> | apply -f <path>"
+	input.ShowSuggestions = true
+	return &CommandPrompt{input: input}
+}
+
+// Open clears the prompt and focuses it.
+func (c *CommandPrompt) Open() tea.Cmd {
+	c.submitted = false
+	c.cancelled = false
+	c.input.Reset()
+	return c.input.Focus()
+}
+
+// SetSuggestions sets the command lines offered for completion with tab.
+func (c *CommandPrompt) SetSuggestions(suggestions []string) {
+	c.input.SetSuggestions(suggestions)
+}
+
+func (c *CommandPrompt) Submitted() bool { return c.submitted }
+func (c *CommandPrompt) Cancelled() bool { return c.cancelled }
+
+// Command returns the entered command line.
+func (c *CommandPrompt) Command() string {
+	return strings.TrimSpace(c.input.Value())
+}
+
+func (c *CommandPrompt) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (c *CommandPrompt) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			c.cancelled = true
+			return c, nil
+		case "enter":
+			c.submitted = true
+			return c, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	c.input, cmd = c.input.Update(msg)
+	return c, cmd
+}
+
+func (c *CommandPrompt) View() string {
+	view := commandPromptStyle.Render(c.input.View())
+	if c.input.Value() == "" {
+		return view
+	}
+
+	matches := c.input.MatchedSuggestions()
+	if len(matches) == 0 {
+		return view
+	}
+	more := ""
+	if len(matches) > maxShownSuggestions {
+		more = fmt.Sprintf("  (+%d)", len(matches)-maxShownSuggestions)
+		matches = matches[:maxShownSuggestions]
+	}
+	return view + "\n" + commandSuggestionStyle.Render(strings.Join(matches, "  ")+more+"  [tab] Complete")
+}
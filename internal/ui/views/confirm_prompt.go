@@ -0,0 +1,74 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ConfirmPrompt guards a change in a protected workspace by asking the user
+// to type the workspace path.
+type ConfirmPrompt struct {
+	workspace string
+	action    string
+	input     textinput.Model
+	confirmed bool
+	cancelled bool
+}
+
+func NewConfirmPrompt() *ConfirmPrompt {
+	input := textinput.New()
+	input.Prompt = ""
+	return &ConfirmPrompt{input: input}
+}
+
+// Open resets the prompt for an action in workspace.
+func (c *ConfirmPrompt) Open(workspace, action string) tea.Cmd {
+	c.workspace = workspace
+	c.action = action
+	c.confirmed = false
+	c.cancelled = false
+	c.input.Reset()
+	c.input.Placeholder = workspace
+	return c.input.Focus()
+}
+
+func (c *ConfirmPrompt) Confirmed() bool { return c.confirmed }
+func (c *ConfirmPrompt) Cancelled() bool { return c.cancelled }
+
+func (c *ConfirmPrompt) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (c *ConfirmPrompt) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			c.cancelled = true
+			return c, nil
+		case "enter":
+			if strings.TrimSpace(c.input.Value()) == c.workspace {
+				c.confirmed = true
+			}
+			return c, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	c.input, cmd = c.input.Update(msg)
+	return c, cmd
+}
+
+func (c *ConfirmPrompt) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Workspace %s is protected.\n\n", lipgloss.NewStyle().Bold(true).Render(c.workspace))
+	fmt.Fprintf(&b, "%s\n\n", c.action)
+	fmt.Fprintf(&b, "Type the workspace path to confirm:\n    %s", c.input.View())
+
+	help := helpStyle.Render("[enter] Confirm  [esc] Cancel")
+	return dialogStyle.Render(b.String()) + "\n" + help
+}
@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/keys"
 )
 
 var contextDocStyle = lipgloss.NewStyle().Margin(1, 2)
@@ -15,34 +18,94 @@ var contextHelpStyle = lipgloss.NewStyle().
 	Foreground(lipgloss.Color("241")).
 	Margin(1, 2)
 
+var (
+	kcpBadgeStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Bold(true)
+	k8sBadgeStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("214")).Bold(true)
+	noneBadgeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
 type ContextItem struct {
-	name string
+	ctx  kcp.ContextInfo
+	kind kcp.ClusterKind
+}
+
+func (i ContextItem) Title() string {
+	title := i.badge() + " " + i.ctx.Name
+	if i.ctx.Current {
+		title += " (current)"
+	}
+	return title
+}
+
+// badge shows whether the context points at kcp or a plain Kubernetes
+// cluster, once the context has been probed.
+func (i ContextItem) badge() string {
+	switch i.kind {
+	case kcp.ClusterKCP:
+		return kcpBadgeStyle.Render("[kcp]")
+	case kcp.ClusterKubernetes:
+		return k8sBadgeStyle.Render("[k8s]")
+	case kcp.ClusterUnknown:
+		return noneBadgeStyle.Render("[ ? ]")
+	}
+	return noneBadgeStyle.Render("[...]")
+}
+
+func (i ContextItem) Description() string {
+	desc := fmt.Sprintf("Server: %s | User: %s", valueOrNone(i.ctx.Server), valueOrNone(i.ctx.User))
+	if i.ctx.Namespace != "" {
+		desc += " | Namespace: " + i.ctx.Namespace
+	}
+	return desc
+}
+
+func (i ContextItem) FilterValue() string { return i.ctx.Name }
+
+// SetKinds shows the probed server kind of each context.
+func (c *ContextSelector) SetKinds(kinds map[string]kcp.ClusterKind) tea.Cmd {
+	items := make([]list.Item, len(c.contexts))
+	for i, ctx := range c.contexts {
+		items[i] = ContextItem{ctx: ctx, kind: kinds[ctx.Name]}
+	}
+	return c.list.SetItems(items)
 }
 
-func (i ContextItem) Title() string       { return i.name }
-func (i ContextItem) Description() string { return "Context" }
-func (i ContextItem) FilterValue() string { return i.name }
+func valueOrNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
 
 type ContextSelector struct {
 	list           list.Model
 	hasSelected    bool
+	canCancel      bool
+	cancelled      bool
 	kubeconfigPath string
-	contexts       []string
+	contexts       []kcp.ContextInfo
 	currentCtx     string
+	keys           *keys.KeyMap
 }
 
-func NewContextSelector(kubeconfigPath string, contexts []string, currentCtx string) *ContextSelector {
+func NewContextSelector(km *keys.KeyMap, kubeconfigPath string, contexts []kcp.ContextInfo, currentCtx string) *ContextSelector {
 	items := make([]list.Item, len(contexts))
+	selected := 0
 	for i, ctx := range contexts {
-		items[i] = ContextItem{name: ctx}
+		items[i] = ContextItem{ctx: ctx}
+		if ctx.Name == currentCtx {
+			selected = i
+		}
 	}
 
 	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	l.KeyMap = keys.List()
 	l.Title = "Select a kubeconfig context"
 	l.SetShowTitle(true)
 	l.SetShowStatusBar(true)
 	l.SetShowHelp(false)
 	l.SetFilteringEnabled(true)
+	l.Select(selected)
 
 	cs := &ContextSelector{
 		list:           l,
@@ -50,6 +113,7 @@ func NewContextSelector(kubeconfigPath string, contexts []string, currentCtx str
 		kubeconfigPath: kubeconfigPath,
 		contexts:       contexts,
 		currentCtx:     currentCtx,
+		keys:           km,
 	}
 	return cs
 }
@@ -64,10 +128,28 @@ func (c *ContextSelector) SelectedContext() string {
 	return ""
 }
 
+// AllowCancel lets esc close the selector without choosing a context.
+func (c *ContextSelector) AllowCancel() {
+	c.canCancel = true
+}
+
+func (c *ContextSelector) Cancelled() bool {
+	return c.cancelled
+}
+
+// Filtering reports whether the user is typing a filter query.
+func (c *ContextSelector) Filtering() bool {
+	return c.list.FilterState() == list.Filtering
+}
+
 func (c *ContextSelector) KubeconfigPath() string {
 	return c.kubeconfigPath
 }
 
+func (c *ContextSelector) Contexts() []kcp.ContextInfo {
+	return c.contexts
+}
+
 func (c *ContextSelector) Init() tea.Cmd {
 	return nil
 }
@@ -76,11 +158,14 @@ func (c *ContextSelector) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if c.list.FilterState() != list.Filtering {
-			switch msg.String() {
-			case "enter":
+			switch {
+			case key.Matches(msg, c.keys.SelectContext):
 				c.hasSelected = true
 				return c, nil
-			case "ctrl+c", "q":
+			case key.Matches(msg, c.keys.CancelContext) && c.canCancel:
+				c.cancelled = true
+				return c, nil
+			case key.Matches(msg, c.keys.ForceQuit, c.keys.Quit):
 				return c, tea.Quit
 			}
 		}
@@ -99,9 +184,13 @@ func (c *ContextSelector) View() string {
 
 	b.WriteString(contextDocStyle.Render(c.list.View()))
 	b.WriteString("\n")
-	b.WriteString(contextHelpStyle.Render(
-		fmt.Sprintf("Current context: %s | [enter] Select  [q] Quit", c.currentCtx),
-	))
+	bindings := []key.Binding{c.keys.SelectContext}
+	if c.canCancel {
+		bindings = append(bindings, keys.As(c.keys.CancelContext, "Back"))
+	}
+	bindings = append(bindings, c.keys.Quit)
+	help := fmt.Sprintf("Active context: %s | %s", c.currentCtx, keys.ShortHelp(bindings...))
+	b.WriteString(contextHelpStyle.Render(help))
 
 	return b.String()
 }
@@ -0,0 +1,21 @@
+package views
+
+import "github.com/charmbracelet/bubbles/list"
+
+// cursorList is the list of a view whose selection is restored when going
+// back and forward. Views embed it instead of holding the list themselves.
+type cursorList struct {
+	list list.Model
+}
+
+// Cursor returns the index of the selected item.
+func (c *cursorList) Cursor() int {
+	return c.list.Index()
+}
+
+// SetCursor selects the item at index, if it exists.
+func (c *cursorList) SetCursor(index int) {
+	if index >= 0 && index < len(c.list.Items()) {
+		c.list.Select(index)
+	}
+}
@@ -0,0 +1,115 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/peter/kcplens/internal/ui/keys"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var dialogStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("203")).
+	Padding(1, 2).
+	Margin(1, 2)
+
+var propagationPolicies = []metav1.DeletionPropagation{
+	metav1.DeletePropagationBackground,
+	metav1.DeletePropagationForeground,
+	metav1.DeletePropagationOrphan,
+}
+
+// DeleteDialog asks for confirmation before deleting an object and lets the
+// user pick the propagation policy and dry-run mode.
+type DeleteDialog struct {
+	keys      *keys.KeyMap
+	kind      string
+	name      string
+	workspace string
+	policy    int
+	dryRun    bool
+	// dryRunOnly keeps dry run enabled, used in read-only mode.
+	dryRunOnly bool
+	confirmed  bool
+	cancelled  bool
+}
+
+func NewDeleteDialog(km *keys.KeyMap) *DeleteDialog {
+	return &DeleteDialog{keys: km}
+}
+
+// Open resets the dialog for a new object. With dryRunOnly set the dialog
+// only offers a dry run.
+func (d *DeleteDialog) Open(kind, name, workspace string, dryRunOnly bool) {
+	d.kind = kind
+	d.name = name
+	d.workspace = workspace
+	d.policy = 0
+	d.dryRun = dryRunOnly
+	d.dryRunOnly = dryRunOnly
+	d.confirmed = false
+	d.cancelled = false
+}
+
+func (d *DeleteDialog) Confirmed() bool { return d.confirmed }
+func (d *DeleteDialog) Cancelled() bool { return d.cancelled }
+func (d *DeleteDialog) DryRun() bool    { return d.dryRun }
+
+func (d *DeleteDialog) Propagation() metav1.DeletionPropagation {
+	return propagationPolicies[d.policy]
+}
+
+func (d *DeleteDialog) Init() tea.Cmd {
+	return nil
+}
+
+func (d *DeleteDialog) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, d.keys.Confirm):
+			d.confirmed = true
+		case key.Matches(msg, d.keys.Cancel):
+			d.cancelled = true
+		case key.Matches(msg, d.keys.Propagation):
+			d.policy = (d.policy + 1) % len(propagationPolicies)
+		case key.Matches(msg, d.keys.DryRun):
+			if !d.dryRunOnly {
+				d.dryRun = !d.dryRun
+			}
+		}
+	}
+	return d, nil
+}
+
+func (d *DeleteDialog) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Delete %s %s\nin workspace %s?\n\n", d.kind, lipgloss.NewStyle().Bold(true).Render(d.name), d.workspace)
+
+	policies := make([]string, len(propagationPolicies))
+	for i, p := range propagationPolicies {
+		if i == d.policy {
+			policies[i] = lipgloss.NewStyle().Bold(true).Underline(true).Render(string(p))
+		} else {
+			policies[i] = string(p)
+		}
+	}
+	fmt.Fprintf(&b, "Propagation: %s\n", strings.Join(policies, " / "))
+
+	dryRun := "off"
+	if d.dryRun {
+		dryRun = "on"
+	}
+	if d.dryRunOnly {
+		dryRun += " (read-only mode)"
+	}
+	fmt.Fprintf(&b, "Dry run:     %s", dryRun)
+
+	k := d.keys
+	help := helpStyle.Render(keys.ShortHelp(keys.As(k.Confirm, "Delete"), k.Propagation, k.DryRun, keys.As(k.Cancel, "Cancel")))
+	return dialogStyle.Render(b.String()) + "\n" + help
+}
@@ -0,0 +1,316 @@
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/peter/kcplens/internal/ui/keys"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// DetailMode selects how the detail pane renders the highlighted item.
+type DetailMode int
+
+const (
+	DetailYAML DetailMode = iota
+	DetailDescribe
+	DetailConditions
+)
+
+func (d DetailMode) String() string {
+	switch d {
+	case DetailDescribe:
+		return "Describe"
+	case DetailConditions:
+		return "Conditions"
+	default:
+		return "YAML"
+	}
+}
+
+// Next returns the mode after d, wrapping around.
+func (d DetailMode) Next() DetailMode {
+	return (d + 1) % 3
+}
+
+var (
+	detailBorderStyle = lipgloss.NewStyle().
+				Border(lipgloss.RoundedBorder()).
+				BorderForeground(lipgloss.Color("62")).
+				Padding(0, 1)
+	detailTitleStyle = lipgloss.NewStyle().Bold(true)
+	detailHintStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
+
+// detailMarginTop aligns the pane with the list next to it.
+const detailMarginTop = 1
+
+// DetailPaneHeight returns the height of a detail pane next to a list view
+// of height, leaving out the help below the list.
+func DetailPaneHeight(height int) int {
+	return height - lipgloss.Height(helpStyle.Render(" ")) - detailMarginTop
+}
+
+// DetailPane shows the item highlighted in a list next to it.
+type DetailPane struct {
+	viewport viewport.Model
+	mode     DetailMode
+	title    string
+	content  string
+	width    int
+	height   int
+	keys     *keys.KeyMap
+}
+
+func NewDetailPane(km *keys.KeyMap) *DetailPane {
+	return &DetailPane{viewport: viewport.New(0, 0), keys: km}
+}
+
+// SetSize sets the outer size of the pane.
+func (d *DetailPane) SetSize(width, height int) {
+	d.width = width
+	d.height = height
+	h, v := detailBorderStyle.GetFrameSize()
+	d.viewport.Width = max(width-h, 0)
+	d.viewport.Height = max(height-v-2, 0)
+}
+
+func (d *DetailPane) Mode() DetailMode {
+	return d.mode
+}
+
+func (d *DetailPane) SetMode(mode DetailMode) {
+	d.mode = mode
+}
+
+// Show renders obj for the item called title. The scroll position is kept
+// while the same item is shown and reset when another one is highlighted.
+func (d *DetailPane) Show(title string, obj interface{}) {
+	content := RenderDetail(obj, d.mode)
+	if title == d.title && content == d.content {
+		return
+	}
+	d.viewport.SetContent(content)
+	if title != d.title {
+		d.viewport.GotoTop()
+	}
+	d.title = title
+	d.content = content
+}
+
+func (d *DetailPane) Update(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	d.viewport, cmd = d.viewport.Update(msg)
+	return cmd
+}
+
+// ScrollDown scrolls the pane by n lines, up if n is negative.
+func (d *DetailPane) ScrollDown(n int) {
+	if n < 0 {
+		d.viewport.ScrollUp(-n)
+		return
+	}
+	d.viewport.ScrollDown(n)
+}
+
+func (d *DetailPane) View() string {
+	title := detailTitleStyle.Render(valueOr(d.title, "Nothing selected")) + detailHintStyle.Render(" · "+d.mode.String())
+	k := d.keys
+	hint := detailHintStyle.Render(keys.ShortHelp(keys.As(k.DetailMode, "Mode"), keys.As(k.SplitNarrower, "Narrower"),
+		keys.As(k.SplitWider, "Wider"), keys.As(k.DetailUp, "Scroll up"), keys.As(k.DetailDown, "Scroll down"), keys.As(k.Split, "Close")))
+	inner := lipgloss.NewStyle().MaxWidth(d.viewport.Width).Render(title) + "\n" +
+		d.viewport.View() + "\n" +
+		lipgloss.NewStyle().MaxWidth(d.viewport.Width).Render(hint)
+	return lipgloss.NewStyle().MarginTop(detailMarginTop).Render(detailBorderStyle.Render(inner))
+}
+
+// RenderDetail renders obj as YAML, as a kubectl describe like summary or
+// as a table of its status conditions. Describe and conditions need a
+// Kubernetes object; anything else is shown as YAML.
+func RenderDetail(obj interface{}, mode DetailMode) string {
+	if obj == nil {
+		return "No details available."
+	}
+
+	raw, isObject := obj.(map[string]interface{})
+	if isObject {
+		_, isObject = raw["metadata"].(map[string]interface{})
+	}
+
+	switch {
+	case mode == DetailDescribe && isObject:
+		return describe(raw)
+	case mode == DetailConditions && isObject:
+		return conditionsTable(raw)
+	case mode == DetailConditions:
+		return "No conditions."
+	}
+	return toYAML(obj)
+}
+
+func toYAML(obj interface{}) string {
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return fmt.Sprintf("Error: %v", err)
+	}
+	return string(out)
+}
+
+func describe(raw map[string]interface{}) string {
+	u := unstructured.Unstructured{Object: raw}
+
+	var b strings.Builder
+	field := func(name, value string) {
+		fmt.Fprintf(&b, "%-13s %s\n", name+":", value)
+	}
+	list := func(name string, values []string) {
+		if len(values) == 0 {
+			field(name, "<none>")
+			return
+		}
+		field(name, values[0])
+		for _, v := range values[1:] {
+			fmt.Fprintf(&b, "%-13s %s\n", "", v)
+		}
+	}
+
+	field("Name", u.GetName())
+	if ns := u.GetNamespace(); ns != "" {
+		field("Namespace", ns)
+	}
+	field("Kind", u.GetKind())
+	field("API Version", u.GetAPIVersion())
+	list("Labels", keyValues(u.GetLabels()))
+
+	annotations := u.GetAnnotations()
+	delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+	list("Annotations", keyValues(annotations))
+
+	if created := u.GetCreationTimestamp(); !created.IsZero() {
+		field("Created", fmt.Sprintf("%s (%s ago)", created.Format(time.RFC3339), age(created.Time)))
+	}
+	if deleted := u.GetDeletionTimestamp(); deleted != nil {
+		field("Deleting", fmt.Sprintf("since %s", deleted.Format(time.RFC3339)))
+	}
+	list("Finalizers", u.GetFinalizers())
+
+	var owners []string
+	for _, ref := range u.GetOwnerReferences() {
+		owners = append(owners, ref.Kind+"/"+ref.Name)
+	}
+	list("Owners", owners)
+
+	for _, section := range []string{"spec", "status"} {
+		value, ok := raw[section]
+		if !ok {
+			continue
+		}
+		if section == "status" {
+			if status, ok := value.(map[string]interface{}); ok {
+				value = withoutConditions(status)
+			}
+		}
+		b.WriteString("\n" + strings.ToUpper(section[:1]) + section[1:] + ":\n")
+		b.WriteString(indent(toYAML(value), "  "))
+	}
+
+	b.WriteString("\nConditions:\n")
+	b.WriteString(indent(conditionsTable(raw), "  "))
+	return b.String()
+}
+
+// conditionsTable lists status.conditions with the most recent first.
+func conditionsTable(raw map[string]interface{}) string {
+	conditions, _, _ := unstructured.NestedSlice(raw, "status", "conditions")
+	if len(conditions) == 0 {
+		return "No conditions.\n"
+	}
+
+	type condition struct {
+		kind, status, reason, message string
+		since                         time.Time
+	}
+	rows := make([]condition, 0, len(conditions))
+	for _, c := range conditions {
+		fields, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		row := condition{
+			kind:    stringField(fields, "type"),
+			status:  stringField(fields, "status"),
+			reason:  stringField(fields, "reason"),
+			message: stringField(fields, "message"),
+		}
+		row.since, _ = time.Parse(time.RFC3339, stringField(fields, "lastTransitionTime"))
+		rows = append(rows, row)
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].since.After(rows[j].since) })
+
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tSTATUS\tREASON\tAGE\tMESSAGE")
+	for _, r := range rows {
+		since := "-"
+		if !r.since.IsZero() {
+			since = age(r.since)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", r.kind, r.status, valueOr(r.reason, "-"), since, r.message)
+	}
+	tw.Flush()
+	return b.String()
+}
+
+func withoutConditions(status map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(status))
+	for k, v := range status {
+		if k != "conditions" {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func stringField(fields map[string]interface{}, key string) string {
+	s, _ := fields[key].(string)
+	return s
+}
+
+func keyValues(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for k, v := range m {
+		out = append(out, k+"="+v)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i, l := range lines {
+		lines[i] = prefix + l
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// age formats the time since t like kubectl does, e.g. 5m or 3d.
+func age(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
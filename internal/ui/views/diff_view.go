@@ -0,0 +1,73 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	diffAddStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	diffRemoveStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	diffHunkStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+)
+
+// DiffView shows a colored unified diff together with the available actions.
+type DiffView struct {
+	viewport viewport.Model
+	title    string
+	help     string
+}
+
+func NewDiffView() *DiffView {
+	return &DiffView{viewport: viewport.New(0, 0)}
+}
+
+// SetContent replaces the title, diff and action help shown by the view.
+func (d *DiffView) SetContent(title, diff, help string) {
+	d.title = title
+	d.help = help
+	d.viewport.SetContent(colorizeDiff(diff))
+	d.viewport.GotoTop()
+}
+
+func colorizeDiff(diff string) string {
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			lines[i] = lipgloss.NewStyle().Bold(true).Render(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = diffAddStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = diffRemoveStyle.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = diffHunkStyle.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (d *DiffView) Init() tea.Cmd {
+	return nil
+}
+
+func (d *DiffView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := docStyle.GetFrameSize()
+		d.viewport.Width = msg.Width - h
+		d.viewport.Height = msg.Height - v - 4
+	}
+
+	var cmd tea.Cmd
+	d.viewport, cmd = d.viewport.Update(msg)
+	return d, cmd
+}
+
+func (d *DiffView) View() string {
+	title := lipgloss.NewStyle().Bold(true).Margin(1, 2, 0, 2).Render(d.title)
+	return title + "\n" + docStyle.Render(d.viewport.View()) + "\n" + helpStyle.Render(d.help)
+}
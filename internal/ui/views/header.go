@@ -0,0 +1,214 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// HeaderHeight is the number of lines the header takes without the tab
+// bar, which adds one more line.
+const HeaderHeight = 2
+
+var (
+	headerStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("252")).Background(lipgloss.Color("236"))
+	headerContextStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("231")).Background(lipgloss.Color("62")).Padding(0, 1)
+	headerLabelStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Background(lipgloss.Color("236"))
+	headerBadgeStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("214")).Background(lipgloss.Color("236"))
+	headerMutedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	statusOKStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("42"))
+	statusSlowStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+	statusErrorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	tabStyle           = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Background(lipgloss.Color("236")).Padding(0, 1)
+	activeTabStyle     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("231")).Background(lipgloss.Color("62")).Padding(0, 1)
+)
+
+var (
+	breadcrumbAncestorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("39"))
+	breadcrumbNoAccessStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Strikethrough(true)
+	breadcrumbCurrentStyle  = lipgloss.NewStyle().Bold(true)
+)
+
+const breadcrumbSeparator = " › "
+
+// HeaderInfo is what the header shows.
+type HeaderInfo struct {
+	Context string
+	Server  string
+	User    string
+	// Kind is "kcp" or "k8s".
+	Kind     string
+	ReadOnly bool
+
+	// Path is the current workspace, empty on plain Kubernetes clusters.
+	Path         string
+	Inaccessible map[string]bool
+	View         string
+
+	Refreshed time.Time
+	Latency   time.Duration
+	Err       error
+
+	// Tabs are the labels of the open tabs, shown when there is more than one.
+	Tabs      []string
+	ActiveTab int
+}
+
+// Header is the bar at the top of every view, showing where the user is
+// and the state of the connection.
+type Header struct {
+	info  HeaderInfo
+	width int
+}
+
+func NewHeader() *Header {
+	return &Header{}
+}
+
+func (h *Header) SetWidth(width int) {
+	h.width = width
+}
+
+func (h *Header) SetInfo(info HeaderInfo) {
+	h.info = info
+}
+
+func (h *Header) View() string {
+	view := h.contextLine() + "\n" + h.locationLine()
+	if len(h.info.Tabs) > 1 {
+		view += "\n" + h.tabLine()
+	}
+	return view
+}
+
+func (h *Header) tabLine() string {
+	tabs := make([]string, len(h.info.Tabs))
+	for i, label := range h.info.Tabs {
+		style := tabStyle
+		if i == h.info.ActiveTab {
+			style = activeTabStyle
+		}
+		tabs[i] = style.Render(label)
+	}
+	return h.fill(strings.Join(tabs, " "), lipgloss.NewStyle())
+}
+
+// TabAt returns the index of the tab at screen position x, y, so that
+// clicking a tab switches to it.
+func (h *Header) TabAt(x, y int) (int, bool) {
+	if y != HeaderHeight || len(h.info.Tabs) < 2 {
+		return 0, false
+	}
+
+	start := 0
+	for i, label := range h.info.Tabs {
+		end := start + lipgloss.Width(tabStyle.Render(label))
+		if x >= start && x < end {
+			return i, true
+		}
+		start = end + 1
+	}
+	return 0, false
+}
+
+func (h *Header) contextLine() string {
+	parts := []string{
+		headerContextStyle.Render(h.info.Context),
+		headerLabelStyle.Render(" server ") + headerStyle.Render(h.info.Server),
+		headerLabelStyle.Render("  user ") + headerStyle.Render(valueOr(h.info.User, "unknown")),
+	}
+	if h.info.Kind != "" {
+		parts = append(parts, headerBadgeStyle.Render("  ["+h.info.Kind+"]"))
+	}
+	if h.info.ReadOnly {
+		parts = append(parts, headerBadgeStyle.Render("  [read-only]"))
+	}
+	return h.fill(strings.Join(parts, ""), headerStyle)
+}
+
+func (h *Header) locationLine() string {
+	var parts []string
+	if h.info.Path != "" {
+		parts = append(parts, " "+h.breadcrumbs())
+	} else {
+		parts = append(parts, " "+breadcrumbCurrentStyle.Render(h.info.Context))
+	}
+	parts = append(parts, h.info.View)
+	if !h.info.Refreshed.IsZero() {
+		parts = append(parts, headerMutedStyle.Render("refreshed "+h.info.Refreshed.Format("15:04:05")))
+	}
+	parts = append(parts, h.connection())
+	return h.fill(strings.Join(parts, headerMutedStyle.Render(" │ ")), lipgloss.NewStyle())
+}
+
+// connection renders a traffic light for the last request.
+func (h *Header) connection() string {
+	switch {
+	case h.info.Err != nil:
+		return statusErrorStyle.Render("● " + h.info.Err.Error())
+	case h.info.Latency == 0:
+		return headerMutedStyle.Render("○ no requests yet")
+	case h.info.Latency < 300*time.Millisecond:
+		return statusOKStyle.Render(fmt.Sprintf("● %dms", h.info.Latency.Milliseconds()))
+	case h.info.Latency < time.Second:
+		return statusSlowStyle.Render(fmt.Sprintf("● %dms", h.info.Latency.Milliseconds()))
+	default:
+		return statusErrorStyle.Render(fmt.Sprintf("● %.1fs", h.info.Latency.Seconds()))
+	}
+}
+
+// fill cuts line to the width of the terminal and pads it with style.
+func (h *Header) fill(line string, style lipgloss.Style) string {
+	if h.width <= 0 {
+		return line
+	}
+	line = lipgloss.NewStyle().MaxWidth(h.width).Render(line)
+	if pad := h.width - lipgloss.Width(line); pad > 0 {
+		line += style.Render(strings.Repeat(" ", pad))
+	}
+	return line
+}
+
+// breadcrumbs renders the current path with one entry per ancestor.
+// Ancestors the user cannot access are greyed out.
+func (h *Header) breadcrumbs() string {
+	segments := strings.Split(h.info.Path, ":")
+	crumbs := make([]string, len(segments))
+	for i, name := range segments {
+		path := strings.Join(segments[:i+1], ":")
+		switch {
+		case i == len(segments)-1:
+			crumbs[i] = breadcrumbCurrentStyle.Render(name)
+		case h.info.Inaccessible[path]:
+			crumbs[i] = breadcrumbNoAccessStyle.Render(name)
+		default:
+			crumbs[i] = breadcrumbAncestorStyle.Render(name)
+		}
+	}
+	return strings.Join(crumbs, headerMutedStyle.Render(breadcrumbSeparator))
+}
+
+// PathAt returns the workspace of the breadcrumb at screen position x, y,
+// so that clicking a breadcrumb navigates there.
+func (h *Header) PathAt(x, y int) (string, bool) {
+	if y != 1 || h.info.Path == "" {
+		return "", false
+	}
+
+	segments := strings.Split(h.info.Path, ":")
+	start := 1
+	for i, name := range segments {
+		end := start + lipgloss.Width(name)
+		if x >= start && x < end {
+			path := strings.Join(segments[:i+1], ":")
+			if h.info.Inaccessible[path] {
+				return "", false
+			}
+			return path, true
+		}
+		start = end + lipgloss.Width(breadcrumbSeparator)
+	}
+	return "", false
+}
@@ -0,0 +1,117 @@
+package views
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/ui/keys"
+)
+
+type HistoryItem struct {
+	index   int
+	title   string
+	current bool
+}
+
+func (i HistoryItem) Title() string {
+	if i.current {
+		return "● " + i.title
+	}
+	return "  " + i.title
+}
+
+func (i HistoryItem) Description() string { return "" }
+func (i HistoryItem) FilterValue() string { return i.title }
+
+// HistoryList shows all visited locations, oldest first, to jump to any of
+// them.
+type HistoryList struct {
+	list      list.Model
+	keys      *keys.KeyMap
+	selected  int
+	cancelled bool
+}
+
+func NewHistoryList(km *keys.KeyMap) *HistoryList {
+	delegate := list.NewDefaultDelegate()
+	delegate.ShowDescription = false
+	delegate.SetSpacing(0)
+
+	l := list.New([]list.Item{}, delegate, 0, 0)
+	l.KeyMap = keys.List()
+	l.Title = "History"
+	l.SetShowHelp(false)
+	return &HistoryList{list: l, keys: km, selected: -1}
+}
+
+// Open shows the described locations with current selected.
+func (h *HistoryList) Open(entries []string, current int) tea.Cmd {
+	h.selected = -1
+	h.cancelled = false
+	h.list.ResetFilter()
+
+	items := make([]list.Item, len(entries))
+	for i, e := range entries {
+		items[i] = HistoryItem{index: i, title: e, current: i == current}
+	}
+	cmd := h.list.SetItems(items)
+	h.list.Select(current)
+	return cmd
+}
+
+// Selected returns the index of the location chosen with enter, or -1.
+func (h *HistoryList) Selected() int { return h.selected }
+
+func (h *HistoryList) Cancelled() bool { return h.cancelled }
+
+func (h *HistoryList) Filtering() bool {
+	return h.list.FilterState() == list.Filtering
+}
+
+func (h *HistoryList) Init() tea.Cmd {
+	return nil
+}
+
+func (h *HistoryList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if h.Filtering() {
+			break
+		}
+		switch {
+		case key.Matches(msg, h.keys.History):
+			h.cancelled = true
+			return h, nil
+		case key.Matches(msg, h.keys.Cancel):
+			// The key that clears an applied filter does that first.
+			if h.list.FilterState() == list.FilterApplied && key.Matches(msg, h.list.KeyMap.ClearFilter) {
+				break
+			}
+			h.cancelled = true
+			return h, nil
+		case key.Matches(msg, h.keys.Confirm):
+			if item, ok := h.list.SelectedItem().(HistoryItem); ok {
+				h.selected = item.index
+			}
+			return h, nil
+		}
+	case tea.WindowSizeMsg:
+		hf, v := docStyle.GetFrameSize()
+		h.list.SetSize(msg.Width-hf, msg.Height-v-2)
+	}
+
+	var cmd tea.Cmd
+	h.list, cmd = h.list.Update(msg)
+	return h, cmd
+}
+
+func (h *HistoryList) View() string {
+	var b strings.Builder
+	b.WriteString(docStyle.Render(h.list.View()))
+	b.WriteString("\n")
+	k := h.keys
+	b.WriteString(helpStyle.Render(keys.ShortHelp(keys.As(k.Confirm, "Go to"), keys.As(h.list.KeyMap.Filter, "Filter"), keys.As(k.Cancel, "Close"), k.Quit)))
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/peter/kcplens/internal/ui/keys"
+)
+
+var (
+	keyHelpGroupStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("39"))
+	keyHelpKeyStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+)
+
+// KeyHelp lists all key bindings, generated from the keymap so that it
+// reflects the user's overrides.
+type KeyHelp struct {
+	viewport viewport.Model
+	keys     *keys.KeyMap
+	closed   bool
+}
+
+func NewKeyHelp(km *keys.KeyMap) *KeyHelp {
+	return &KeyHelp{viewport: viewport.New(0, 0), keys: km}
+}
+
+// Open renders the bindings and scrolls to the top.
+func (h *KeyHelp) Open() {
+	h.closed = false
+	h.viewport.SetContent(h.content())
+	h.viewport.GotoTop()
+}
+
+func (h *KeyHelp) content() string {
+	var b strings.Builder
+	for i, group := range h.keys.Groups() {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(keyHelpGroupStyle.Render(group.Title) + "\n")
+		for _, binding := range group.Bindings {
+			if !binding.Enabled() {
+				continue
+			}
+			help := binding.Help()
+			fmt.Fprintf(&b, "  %s %s\n", keyHelpKeyStyle.Render(fmt.Sprintf("%-16s", help.Key)), help.Desc)
+		}
+	}
+	return b.String()
+}
+
+// Closed reports whether the user closed the overlay.
+func (h *KeyHelp) Closed() bool {
+	return h.closed
+}
+
+func (h *KeyHelp) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, h.keys.Help, h.keys.Cancel) {
+			h.closed = true
+			return h, nil
+		}
+	case tea.WindowSizeMsg:
+		horizontal, vertical := docStyle.GetFrameSize()
+		h.viewport.Width = msg.Width - horizontal
+		h.viewport.Height = msg.Height - vertical - 4
+	}
+
+	var cmd tea.Cmd
+	h.viewport, cmd = h.viewport.Update(msg)
+	return h, cmd
+}
+
+func (h *KeyHelp) Init() tea.Cmd {
+	return nil
+}
+
+func (h *KeyHelp) View() string {
+	title := lipgloss.NewStyle().Bold(true).Margin(1, 2, 0, 2).Render("Key bindings")
+	var closing []string
+	for _, b := range []key.Binding{h.keys.Cancel, h.keys.Help} {
+		if b.Enabled() {
+			closing = append(closing, b.Help().Key)
+		}
+	}
+	help := helpStyle.Render(fmt.Sprintf("[↑/↓] Scroll  [%s] Close", strings.Join(closing, "/")))
+	return title + "\n" + docStyle.Render(h.viewport.View()) + "\n" + help
+}
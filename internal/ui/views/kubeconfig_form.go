@@ -0,0 +1,125 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/peter/kcplens/internal/ui/keys"
+)
+
+const (
+	kubeconfigFieldPath = iota
+	kubeconfigFieldContext
+	kubeconfigFieldCount
+)
+
+// KubeconfigForm asks for the file and context name of a kubeconfig
+// generated for a workspace.
+type KubeconfigForm struct {
+	keys      *keys.KeyMap
+	workspace string
+	path      textinput.Model
+	context   textinput.Model
+	focus     int
+	submitted bool
+	cancelled bool
+}
+
+func NewKubeconfigForm(km *keys.KeyMap) *KubeconfigForm {
+	path := textinput.New()
+	path.Prompt = ""
+
+	context := textinput.New()
+	context.Prompt = ""
+
+	return &KubeconfigForm{keys: km, path: path, context: context}
+}
+
+// Open resets the form for workspace with the given defaults.
+func (f *KubeconfigForm) Open(workspace, path, context string) tea.Cmd {
+	f.workspace = workspace
+	f.focus = kubeconfigFieldPath
+	f.submitted = false
+	f.cancelled = false
+	f.path.SetValue(path)
+	f.path.CursorEnd()
+	f.context.SetValue(context)
+	f.context.CursorEnd()
+	f.context.Blur()
+	return f.path.Focus()
+}
+
+func (f *KubeconfigForm) Submitted() bool { return f.submitted }
+func (f *KubeconfigForm) Cancelled() bool { return f.cancelled }
+
+func (f *KubeconfigForm) Workspace() string { return f.workspace }
+func (f *KubeconfigForm) Path() string      { return strings.TrimSpace(f.path.Value()) }
+func (f *KubeconfigForm) Context() string   { return strings.TrimSpace(f.context.Value()) }
+
+func (f *KubeconfigForm) setFocus(field int) tea.Cmd {
+	f.focus = (field + kubeconfigFieldCount) % kubeconfigFieldCount
+	f.path.Blur()
+	f.context.Blur()
+	if f.focus == kubeconfigFieldPath {
+		return f.path.Focus()
+	}
+	return f.context.Focus()
+}
+
+func (f *KubeconfigForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (f *KubeconfigForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, f.keys.CancelForm):
+			f.cancelled = true
+			return f, nil
+		case key.Matches(msg, f.keys.NextField):
+			return f, f.setFocus(f.focus + 1)
+		case key.Matches(msg, f.keys.PrevField):
+			return f, f.setFocus(f.focus - 1)
+		case key.Matches(msg, f.keys.Submit):
+			if f.focus < kubeconfigFieldContext {
+				return f, f.setFocus(f.focus + 1)
+			}
+			if f.Path() != "" && f.Context() != "" {
+				f.submitted = true
+			}
+			return f, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch f.focus {
+	case kubeconfigFieldPath:
+		f.path, cmd = f.path.Update(msg)
+	case kubeconfigFieldContext:
+		f.context, cmd = f.context.Update(msg)
+	}
+	return f, cmd
+}
+
+func (f *KubeconfigForm) label(field int, text string) string {
+	if f.focus == field {
+		return focusedLabelStyle.Render("> " + text)
+	}
+	return "  " + text
+}
+
+func (f *KubeconfigForm) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Kubeconfig for %s\n\n", lipgloss.NewStyle().Bold(true).Render(f.workspace))
+	fmt.Fprintf(&b, "%s\n    %s\n\n", f.label(kubeconfigFieldPath, "File"), f.path.View())
+	fmt.Fprintf(&b, "%s\n    %s", f.label(kubeconfigFieldContext, "Context name"), f.context.View())
+
+	k := f.keys
+	help := helpStyle.Render(keys.ShortHelp(k.NextField, k.PrevField, keys.As(k.Submit, "Next field, write on the last"), k.CancelForm))
+	return formStyle.Render(b.String()) + "\n" + help
+}
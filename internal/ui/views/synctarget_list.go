@@ -7,6 +7,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/keys"
 )
 
 type SyncTargetItem struct {
@@ -20,13 +21,15 @@ func (i SyncTargetItem) Description() string {
 func (i SyncTargetItem) FilterValue() string { return i.target.Name }
 
 type SyncTargetList struct {
-	list list.Model
+	cursorList
+	keys *keys.KeyMap
 }
 
-func NewSyncTargetList() *SyncTargetList {
+func NewSyncTargetList(km *keys.KeyMap) *SyncTargetList {
 	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.KeyMap = keys.List()
 	l.Title = "Sync Targets (Physical Clusters)"
-	return &SyncTargetList{list: l}
+	return &SyncTargetList{cursorList: cursorList{l}, keys: km}
 }
 
 func (s *SyncTargetList) SetItems(targets []kcp.SyncTarget) tea.Cmd {
@@ -54,6 +57,20 @@ func (s *SyncTargetList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (s *SyncTargetList) View() string {
-	help := helpStyle.Render("[backspace/esc] Back  [q] Quit")
+	help := helpStyle.Render(keys.ShortHelp(s.keys.Back, keys.As(s.keys.Help, "Help"), s.keys.Quit))
 	return docStyle.Render(s.list.View()) + "\n" + help
 }
+
+// Detail returns the highlighted sync target for the detail pane.
+func (s *SyncTargetList) Detail() (string, interface{}) {
+	item, ok := s.list.SelectedItem().(SyncTargetItem)
+	if !ok || item.target.Raw == nil {
+		return "", nil
+	}
+	return item.target.Name, item.target.Raw
+}
+
+// Filtering reports whether the user is typing a filter query.
+func (s *SyncTargetList) Filtering() bool {
+	return s.list.FilterState() == list.Filtering
+}
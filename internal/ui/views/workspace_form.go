@@ -0,0 +1,171 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/keys"
+)
+
+var formStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("62")).
+	Padding(1, 2).
+	Margin(1, 2)
+
+var focusedLabelStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("212"))
+
+const (
+	workspaceFieldName = iota
+	workspaceFieldType
+	workspaceFieldLocation
+	workspaceFieldCount
+)
+
+// WorkspaceForm collects the name, type and location of a new workspace.
+type WorkspaceForm struct {
+	keys      *keys.KeyMap
+	parent    string
+	name      textinput.Model
+	location  textinput.Model
+	types     []kcp.WorkspaceTypeRef
+	typeIdx   int // 0 is the server default, i > 0 selects types[i-1]
+	focus     int
+	submitted bool
+	cancelled bool
+}
+
+func NewWorkspaceForm(km *keys.KeyMap) *WorkspaceForm {
+	name := textinput.New()
+	name.Placeholder = "my-workspace"
+	name.Prompt = ""
+	name.CharLimit = 63
+
+	location := textinput.New()
+	location.Placeholder = "optional, e.g. region=eu"
+	location.Prompt = ""
+
+	return &WorkspaceForm{keys: km, name: name, location: location}
+}
+
+// Open resets the form for creating a workspace under parent.
+func (f *WorkspaceForm) Open(parent string, types []kcp.WorkspaceTypeRef) tea.Cmd {
+	f.parent = parent
+	f.types = types
+	f.typeIdx = 0
+	f.focus = workspaceFieldName
+	f.submitted = false
+	f.cancelled = false
+	f.name.Reset()
+	f.location.Reset()
+	f.location.Blur()
+	return f.name.Focus()
+}
+
+func (f *WorkspaceForm) Submitted() bool { return f.submitted }
+func (f *WorkspaceForm) Cancelled() bool { return f.cancelled }
+
+// Workspace returns the workspace described by the form.
+func (f *WorkspaceForm) Workspace() kcp.NewWorkspace {
+	ws := kcp.NewWorkspace{
+		Name:             strings.TrimSpace(f.name.Value()),
+		LocationSelector: strings.TrimSpace(f.location.Value()),
+	}
+	if f.typeIdx > 0 {
+		t := f.types[f.typeIdx-1]
+		ws.Type = &t
+	}
+	return ws
+}
+
+func (f *WorkspaceForm) setFocus(field int) tea.Cmd {
+	f.focus = (field + workspaceFieldCount) % workspaceFieldCount
+	f.name.Blur()
+	f.location.Blur()
+	switch f.focus {
+	case workspaceFieldName:
+		return f.name.Focus()
+	case workspaceFieldLocation:
+		return f.location.Focus()
+	}
+	return nil
+}
+
+func (f *WorkspaceForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (f *WorkspaceForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, f.keys.CancelForm):
+			f.cancelled = true
+			return f, nil
+		case key.Matches(msg, f.keys.NextField):
+			return f, f.setFocus(f.focus + 1)
+		case key.Matches(msg, f.keys.PrevField):
+			return f, f.setFocus(f.focus - 1)
+		case key.Matches(msg, f.keys.Submit):
+			if f.focus < workspaceFieldLocation {
+				return f, f.setFocus(f.focus + 1)
+			}
+			if strings.TrimSpace(f.name.Value()) != "" {
+				f.submitted = true
+			}
+			return f, nil
+		}
+
+		if f.focus == workspaceFieldType {
+			switch msg.String() {
+			case "left", "h":
+				f.typeIdx = (f.typeIdx + len(f.types)) % (len(f.types) + 1)
+			case "right", "l", " ":
+				f.typeIdx = (f.typeIdx + 1) % (len(f.types) + 1)
+			}
+			return f, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch f.focus {
+	case workspaceFieldName:
+		f.name, cmd = f.name.Update(msg)
+	case workspaceFieldLocation:
+		f.location, cmd = f.location.Update(msg)
+	}
+	return f, cmd
+}
+
+func (f *WorkspaceForm) label(field int, text string) string {
+	if f.focus == field {
+		return focusedLabelStyle.Render("> " + text)
+	}
+	return "  " + text
+}
+
+func (f *WorkspaceForm) typeName() string {
+	if f.typeIdx == 0 {
+		return "(server default)"
+	}
+	t := f.types[f.typeIdx-1]
+	return fmt.Sprintf("%s (from %s)", t.Name, t.Path)
+}
+
+func (f *WorkspaceForm) View() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "New workspace in %s\n\n", lipgloss.NewStyle().Bold(true).Render(f.parent))
+	fmt.Fprintf(&b, "%s\n    %s\n\n", f.label(workspaceFieldName, "Name"), f.name.View())
+	fmt.Fprintf(&b, "%s\n    < %s >\n\n", f.label(workspaceFieldType, "Type"), f.typeName())
+	fmt.Fprintf(&b, "%s\n    %s", f.label(workspaceFieldLocation, "Location selector"), f.location.View())
+
+	k := f.keys
+	help := helpStyle.Render(keys.ShortHelp(k.NextField, k.PrevField) + "  [←/→] Choose type  " +
+		keys.ShortHelp(keys.As(k.Submit, "Next field, create on the last"), k.CancelForm))
+	return formStyle.Render(b.String()) + "\n" + help
+}
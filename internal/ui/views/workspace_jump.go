@@ -0,0 +1,161 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// maxJumpMatches limits the matches listed by the workspace jump prompt.
+const maxJumpMatches = 10
+
+var (
+	jumpMatchStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("39")).Bold(true)
+	jumpSelectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170")).Bold(true)
+)
+
+// WorkspaceJump asks for a workspace path, fuzzy matching against all
+// workspaces known so far.
+type WorkspaceJump struct {
+	input     textinput.Model
+	paths     []string
+	matches   []fuzzy.Match
+	selected  int
+	indexing  bool
+	submitted bool
+	cancelled bool
+}
+
+func NewWorkspaceJump() *WorkspaceJump {
+	input := textinput.New()
+	input.Prompt = "Go to workspace: "
+	input.Placeholder = "path or part of it"
+	return &WorkspaceJump{input: input}
+}
+
+// Open clears the prompt and focuses it.
+func (w *WorkspaceJump) Open() tea.Cmd {
+	w.submitted = false
+	w.cancelled = false
+	w.selected = 0
+	w.input.Reset()
+	w.match()
+	return w.input.Focus()
+}
+
+// SetPaths replaces the workspace paths matched against. indexing tells
+// whether more paths are still being discovered.
+func (w *WorkspaceJump) SetPaths(paths []string, indexing bool) {
+	w.paths = paths
+	w.indexing = indexing
+	w.match()
+}
+
+func (w *WorkspaceJump) Submitted() bool { return w.submitted }
+func (w *WorkspaceJump) Cancelled() bool { return w.cancelled }
+
+// SelectedPath returns the highlighted match, or the entered text if nothing
+// matches, so that paths not discovered yet can be entered verbatim.
+func (w *WorkspaceJump) SelectedPath() string {
+	if w.selected < len(w.matches) {
+		return w.matches[w.selected].Str
+	}
+	return strings.TrimSpace(w.input.Value())
+}
+
+func (w *WorkspaceJump) match() {
+	pattern := strings.TrimSpace(w.input.Value())
+	if pattern == "" {
+		w.matches = nil
+	} else {
+		w.matches = fuzzy.Find(pattern, w.paths)
+	}
+	if w.selected >= len(w.matches) {
+		w.selected = 0
+	}
+}
+
+func (w *WorkspaceJump) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (w *WorkspaceJump) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "esc":
+			w.cancelled = true
+			return w, nil
+		case "enter":
+			if w.SelectedPath() != "" {
+				w.submitted = true
+			}
+			return w, nil
+		case "down", "ctrl+n", "tab":
+			if w.selected < min(len(w.matches), maxJumpMatches)-1 {
+				w.selected++
+			}
+			return w, nil
+		case "up", "ctrl+p", "shift+tab":
+			if w.selected > 0 {
+				w.selected--
+			}
+			return w, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	w.input, cmd = w.input.Update(msg)
+	w.match()
+	return w, cmd
+}
+
+// highlight renders a match with the matched characters emphasized.
+func highlight(m fuzzy.Match) string {
+	matched := make(map[int]bool, len(m.MatchedIndexes))
+	for _, i := range m.MatchedIndexes {
+		matched[i] = true
+	}
+
+	var b strings.Builder
+	for i, r := range m.Str {
+		if matched[i] {
+			b.WriteString(jumpMatchStyle.Render(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (w *WorkspaceJump) View() string {
+	var b strings.Builder
+
+	b.WriteString(w.input.View())
+	b.WriteString("\n\n")
+	for i, m := range w.matches {
+		if i == maxJumpMatches {
+			fmt.Fprintf(&b, "  … %d more\n", len(w.matches)-maxJumpMatches)
+			break
+		}
+		if i == w.selected {
+			b.WriteString(jumpSelectedStyle.Render("> ") + highlight(m) + "\n")
+		} else {
+			b.WriteString("  " + highlight(m) + "\n")
+		}
+	}
+	if len(w.matches) == 0 && strings.TrimSpace(w.input.Value()) != "" {
+		b.WriteString(emptyStyle.Render("No known workspace matches, enter jumps to the path as typed."))
+		b.WriteString("\n")
+	}
+
+	status := fmt.Sprintf("%d workspaces known", len(w.paths))
+	if w.indexing {
+		status += ", indexing…"
+	}
+	help := helpStyle.Render(status + "  [up/down] Select  [enter] Go  [esc] Cancel")
+	return formStyle.Render(b.String()) + "\n" + help
+}
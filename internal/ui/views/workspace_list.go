@@ -8,6 +8,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/peter/kcplens/internal/kcp"
+	"github.com/peter/kcplens/internal/ui/keys"
 )
 
 var docStyle = lipgloss.NewStyle().Margin(1, 2)
@@ -25,18 +26,34 @@ type WorkspaceItem struct {
 	node *kcp.WorkspaceNode
 }
 
-func (i WorkspaceItem) Title() string       { return i.node.Name }
-func (i WorkspaceItem) Description() string { return "Path: " + i.node.Path }
+func (i WorkspaceItem) Title() string { return i.node.Name }
+func (i WorkspaceItem) Description() string {
+	return fmt.Sprintf("Path: %s | Phase: %s%s", i.node.Path, i.node.Phase, terminatingInfo(i.node.Deleting, i.node.Finalizers))
+}
 func (i WorkspaceItem) FilterValue() string { return i.node.Name + " " + i.node.Path }
 
+// terminatingInfo describes an object that is being deleted and the
+// finalizers still holding it.
+func terminatingInfo(deleting bool, finalizers []string) string {
+	if !deleting {
+		return ""
+	}
+	if len(finalizers) == 0 {
+		return " | Terminating"
+	}
+	return fmt.Sprintf(" | Terminating (finalizers: %s)", strings.Join(finalizers, ", "))
+}
+
 type WorkspaceList struct {
-	list             list.Model
+	cursorList
 	currentPath      string
 	hasSubWorkspaces bool
+	keys             *keys.KeyMap
 }
 
-func NewWorkspaceList() *WorkspaceList {
+func NewWorkspaceList(km *keys.KeyMap) *WorkspaceList {
 	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
+	l.KeyMap = keys.List()
 	l.Title = "KCP Workspaces"
 	l.SetShowTitle(true)
 	l.SetShowStatusBar(false)
@@ -44,8 +61,9 @@ func NewWorkspaceList() *WorkspaceList {
 	l.SetFilteringEnabled(false)
 
 	return &WorkspaceList{
-		list:        l,
+		cursorList:  cursorList{l},
 		currentPath: "root",
+		keys:        km,
 	}
 }
 
@@ -75,7 +93,7 @@ func (w *WorkspaceList) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case tea.WindowSizeMsg:
 		h, v := docStyle.GetFrameSize()
-		w.list.SetSize(msg.Width-h, msg.Height-v-4)
+		w.list.SetSize(msg.Width-h, msg.Height-v-3)
 	}
 
 	var cmd tea.Cmd
@@ -89,22 +107,34 @@ func (w *WorkspaceList) View() string {
 	if w.hasSubWorkspaces {
 		b.WriteString(docStyle.Render(w.list.View()))
 		b.WriteString("\n")
-		b.WriteString(helpStyle.Render(
-			fmt.Sprintf("Current: %s | [a] APIs  [s] SyncTargets  [r] Resources  [enter] Navigate  [backspace] Back  [q] Quit", w.currentPath),
-		))
+		k := w.keys
+		b.WriteString(helpStyle.Render(keys.ShortHelp(k.APIs, k.SyncTargets, k.Resources, keys.As(k.Open, "Navigate"), k.Jump, k.NewWorkspace,
+			k.Bind, k.ExportKubeconfig, k.Delete, k.AuditLog, k.Back, keys.As(k.Help, "Help"), k.Quit)))
 	} else {
 		b.WriteString(docStyle.Render(w.list.Title))
 		b.WriteString("\n\n")
 		b.WriteString(emptyStyle.Render("No sub-workspaces. Use the commands below to explore this workspace."))
-		b.WriteString("\n\n")
-		b.WriteString(helpStyle.Render(
-			fmt.Sprintf("Current: %s | [a] APIs  [s] SyncTargets  [r] Resources  [backspace] Back  [q] Quit", w.currentPath),
-		))
+		b.WriteString("\n")
+		k := w.keys
+		b.WriteString(helpStyle.Render(keys.ShortHelp(k.APIs, k.SyncTargets, k.Resources, k.Jump, k.NewWorkspace,
+			k.Bind, k.ExportKubeconfig, k.AuditLog, k.Back, keys.As(k.Help, "Help"), k.Quit)))
 	}
 
 	return b.String()
 }
 
+// Paths returns the paths of all listed workspaces.
+func (w *WorkspaceList) Paths() []string {
+	items := w.list.Items()
+	paths := make([]string, 0, len(items))
+	for _, i := range items {
+		if wi, ok := i.(WorkspaceItem); ok {
+			paths = append(paths, wi.node.Path)
+		}
+	}
+	return paths
+}
+
 func (w *WorkspaceList) SelectedNode() *kcp.WorkspaceNode {
 	i := w.list.SelectedItem()
 	if i == nil {
@@ -115,3 +145,12 @@ func (w *WorkspaceList) SelectedNode() *kcp.WorkspaceNode {
 	}
 	return nil
 }
+
+// Detail returns the highlighted workspace for the detail pane.
+func (w *WorkspaceList) Detail() (string, interface{}) {
+	node := w.SelectedNode()
+	if node == nil || node.Raw == nil {
+		return "", nil
+	}
+	return node.Path, node.Raw
+}
@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"context"
+	"sort"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/peter/kcplens/internal/kcp"
+)
+
+// maxIndexedWorkspaces stops crawling in very large installations.
+const maxIndexedWorkspaces = 5000
+
+// workspaceIndexedMsg carries the children of a workspace listed by the
+// background crawl of the workspace index of a context.
+type workspaceIndexedMsg struct {
+	context  string
+	path     string
+	children []*kcp.WorkspaceNode
+}
+
+// workspaceIndex collects the workspace paths of a context for the jump
+// prompt. It is filled from navigation and by crawling the hierarchy one
+// workspace at a time in the background.
+type workspaceIndex struct {
+	cm       *kcp.ClientManager
+	paths    map[string]bool
+	queue    []string
+	crawling bool
+	// current is the workspace being listed while crawling.
+	current string
+}
+
+func newWorkspaceIndex(cm *kcp.ClientManager) *workspaceIndex {
+	return &workspaceIndex{cm: cm, paths: make(map[string]bool)}
+}
+
+// clone copies the index for a tab crawling with cm. A crawl in progress is
+// continued by the original index only, so its path is queued again.
+func (x *workspaceIndex) clone(cm *kcp.ClientManager) *workspaceIndex {
+	c := newWorkspaceIndex(cm)
+	for path := range x.paths {
+		c.paths[path] = true
+	}
+	if x.crawling {
+		c.queue = append(c.queue, x.current)
+	}
+	c.queue = append(c.queue, x.queue...)
+	return c
+}
+
+// add records paths and queues new ones for crawling.
+func (x *workspaceIndex) add(paths ...string) {
+	for _, path := range paths {
+		if path == "" || x.paths[path] {
+			continue
+		}
+		x.paths[path] = true
+		x.queue = append(x.queue, path)
+	}
+}
+
+func (x *workspaceIndex) sorted() []string {
+	paths := make([]string, 0, len(x.paths))
+	for path := range x.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// indexing reports whether the crawl has not finished yet.
+func (x *workspaceIndex) indexing() bool {
+	return x.crawling || (len(x.queue) > 0 && len(x.paths) < maxIndexedWorkspaces)
+}
+
+// next lists the children of the next queued workspace, unless a crawl is
+// already running.
+func (x *workspaceIndex) next() tea.Cmd {
+	if x.crawling || len(x.queue) == 0 || len(x.paths) >= maxIndexedWorkspaces {
+		return nil
+	}
+	path := x.queue[0]
+	x.queue = x.queue[1:]
+	x.crawling = true
+	x.current = path
+
+	cm := x.cm
+	return func() tea.Msg {
+		// Workspaces that cannot be listed simply contribute no children.
+		children, _ := cm.ListChildWorkspaces(context.Background(), path)
+		return workspaceIndexedMsg{context: cm.ContextName(), path: path, children: children}
+	}
+}
+
+// workspaceIndex returns the index of the active context, creating and
+// seeding it with root and the kubeconfig's workspace on first use.
+func (m *AppModel) workspaceIndex() *workspaceIndex {
+	name := m.clientMgr.ContextName()
+	if idx, ok := m.workspaceIndexes[name]; ok {
+		return idx
+	}
+	idx := newWorkspaceIndex(m.clientMgr)
+	idx.add("root")
+	idx.add(kcp.Ancestors(m.clientMgr.InitialWorkspace())...)
+	idx.add(m.clientMgr.InitialWorkspace())
+	m.workspaceIndexes[name] = idx
+	return idx
+}
+
+// indexWorkspacesCmd continues crawling the workspaces of the active context.
+func (m *AppModel) indexWorkspacesCmd() tea.Cmd {
+	if !m.clientMgr.IsKCP() {
+		return nil
+	}
+	return m.workspaceIndex().next()
+}
+
+func (m *AppModel) handleWorkspaceIndexedMsg(msg workspaceIndexedMsg) tea.Cmd {
+	idx, ok := m.workspaceIndexes[msg.context]
+	if !ok {
+		return nil
+	}
+	idx.crawling = false
+	for _, child := range msg.children {
+		// Workspaces that are not ready yet or being deleted cannot be listed.
+		if child.Phase != "Ready" || child.Deleting {
+			idx.paths[child.Path] = true
+			continue
+		}
+		idx.add(child.Path)
+	}
+
+	if msg.context != m.clientMgr.ContextName() {
+		// Resumed when the context becomes active again.
+		return nil
+	}
+	if m.jumping {
+		m.workspaceJump.SetPaths(idx.sorted(), idx.indexing())
+	}
+	return idx.next()
+}
+
+// startJump opens the prompt to go to any known workspace.
+func (m *AppModel) startJump() tea.Cmd {
+	if m.listFiltering() || !m.clientMgr.IsKCP() {
+		return nil
+	}
+	idx := m.workspaceIndex()
+	idx.add(m.clientMgr.CurrentWorkspace())
+	idx.add(m.workspaceList.Paths()...)
+
+	m.jumping = true
+	cmd := m.workspaceJump.Open()
+	m.workspaceJump.SetPaths(idx.sorted(), idx.indexing())
+	return tea.Batch(cmd, idx.next())
+}
+
+func (m *AppModel) handleJumpKey(msg tea.KeyMsg) tea.Cmd {
+	_, cmd := m.workspaceJump.Update(msg)
+
+	if m.workspaceJump.Cancelled() {
+		m.jumping = false
+		return nil
+	}
+	if !m.workspaceJump.Submitted() {
+		return cmd
+	}
+
+	m.jumping = false
+	return m.goToWorkspace(m.workspaceJump.SelectedPath())
+}